@@ -1,9 +1,11 @@
 package domain
 
+import "context"
+
 type PlayerRepository interface {
-	Store(player Player) error
-	FindById(id int) (Player, error, int)
-	NameMatchesId(playerName string, id int) (bool, error)
+	Store(ctx context.Context, player Player) (int, error)
+	FindById(ctx context.Context, id int) (Player, error, int)
+	NameMatchesId(ctx context.Context, playerName string, id int) (bool, error)
 }
 
 type Player struct {
@@ -0,0 +1,268 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"game-tracker/models/postgres"
+)
+
+// LoadConfiguration reads the configuration in layers, each overriding the
+// one before: the JSON file, GAMETRACKER_* environment variables, then the
+// flags in args. Every field has an environment variable and a flag named
+// after it, like GAMETRACKER_LISTEN_ADDRESS and -listen-address. Lists are
+// comma separated, RouteTimeouts are route=seconds pairs.
+//
+// The file is -config, else GAMETRACKER_CONFIG, else config.json when it
+// exists. Secrets can be read from the files named by PostgresAdrFile and
+// JWTKeysFile instead.
+func LoadConfiguration(args []string) (postgres.Configuration, error) {
+	config := postgres.Configuration{}
+	flags := flag.NewFlagSet("game-tracker", flag.ContinueOnError)
+	path := flags.String("config", "", "configuration file")
+	values := configFlags(flags)
+	err := flags.Parse(args)
+	if err != nil {
+		return config, err
+	}
+
+	required := true
+	if *path == "" {
+		*path = os.Getenv("GAMETRACKER_CONFIG")
+	}
+	if *path == "" {
+		*path = "config.json"
+		required = false
+	}
+	file, err := os.Open(*path)
+	switch {
+	case err == nil:
+		defer file.Close()
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&config)
+		if err != nil {
+			return config, fmt.Errorf("Cannot read %s: %v", *path, err)
+		}
+	case required || !errors.Is(err, os.ErrNotExist):
+		return config, fmt.Errorf("Cannot open configuration: %v", err)
+	}
+
+	fields := reflect.ValueOf(&config).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Type().Field(i).Name
+		env := "GAMETRACKER_" + strings.ToUpper(strings.Join(nameWords(name), "_"))
+		if value, ok := os.LookupEnv(env); ok {
+			err = setField(fields.Field(i), value)
+			if err != nil {
+				return config, fmt.Errorf("%s: %v", env, err)
+			}
+		}
+	}
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "config" || err != nil {
+			return
+		}
+		err = setField(fields.FieldByName(values[f.Name]), f.Value.String())
+		if err != nil {
+			err = fmt.Errorf("-%s: %v", f.Name, err)
+		}
+	})
+	if err != nil {
+		return config, err
+	}
+
+	err = readSecrets(&config)
+	if err != nil {
+		return config, err
+	}
+	return config, validate(config)
+}
+
+// configFlags declares a flag for every field, giving the field of each flag
+func configFlags(flags *flag.FlagSet) map[string]string {
+	values := make(map[string]string)
+	fields := reflect.TypeOf(postgres.Configuration{})
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Field(i).Name
+		flagName := strings.ToLower(strings.Join(nameWords(name), "-"))
+		flags.String(flagName, "", "overrides "+name)
+		values[flagName] = name
+	}
+	return values
+}
+
+// nameWords splits a field name in words, keeping acronyms whole:
+// TLSCertFile gives TLS, Cert and File
+func nameWords(name string) []string {
+	lower := func(i int) bool {
+		return name[i] >= 'a' && name[i] <= 'z' || name[i] >= '0' && name[i] <= '9'
+	}
+	words := []string{}
+	start := 0
+	for i := 1; i < len(name); i++ {
+		if !lower(i) && (lower(i-1) || i+1 < len(name) && lower(i+1)) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	return append(words, name[start:])
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Slice:
+		list := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+	case reflect.Map:
+		timeouts := make(map[string]int)
+		for _, pair := range strings.Split(value, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			route, seconds, found := strings.Cut(pair, "=")
+			n, err := strconv.Atoi(strings.TrimSpace(seconds))
+			if !found || err != nil {
+				return fmt.Errorf("%q is not route=seconds", pair)
+			}
+			timeouts[strings.TrimSpace(route)] = n
+		}
+		field.Set(reflect.ValueOf(timeouts))
+	default:
+		return fmt.Errorf("Cannot set a %s", field.Kind())
+	}
+	return nil
+}
+
+// readSecrets fills the secrets kept out of the configuration in files
+func readSecrets(config *postgres.Configuration) error {
+	if config.PostgresAdrFile != "" {
+		if config.PostgresAdr != "" {
+			return fmt.Errorf("Set PostgresAdr or PostgresAdrFile, not both")
+		}
+		content, err := os.ReadFile(config.PostgresAdrFile)
+		if err != nil {
+			return fmt.Errorf("Cannot read PostgresAdrFile: %v", err)
+		}
+		config.PostgresAdr = strings.TrimSpace(string(content))
+	}
+	if config.JWTKeysFile != "" {
+		if len(config.JWTKeys) != 0 {
+			return fmt.Errorf("Set JWTKeys or JWTKeysFile, not both")
+		}
+		content, err := os.ReadFile(config.JWTKeysFile)
+		if err != nil {
+			return fmt.Errorf("Cannot read JWTKeysFile: %v", err)
+		}
+		for _, key := range strings.Split(string(content), "\n") {
+			if key = strings.TrimSpace(key); key != "" {
+				config.JWTKeys = append(config.JWTKeys, key)
+			}
+		}
+	}
+	return nil
+}
+
+// validate refuses configurations the server cannot start with, telling
+// every problem at once
+func validate(config postgres.Configuration) error {
+	problems := []string{}
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if config.PostgresAdr == "" {
+		problem("PostgresAdr or PostgresAdrFile is required")
+	}
+	if len(config.JWTKeys) == 0 {
+		problem("JWTKeys or JWTKeysFile is required")
+	}
+	for i, key := range config.JWTKeys {
+		if len(key) < 16 {
+			problem("JWT key #%d is shorter than 16 characters", i+1)
+		}
+	}
+	if config.BaseURL != "" {
+		base, err := url.Parse(config.BaseURL)
+		if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+			problem("BaseURL %q is not an http or https URL", config.BaseURL)
+		}
+	}
+	for _, origin := range config.CORSOrigins {
+		parsed, err := url.Parse(origin)
+		if origin != "*" && (err != nil || parsed.Scheme == "" || parsed.Host == "" ||
+			strings.TrimSuffix(parsed.Path, "/") != "") {
+			problem("CORS origin %q is not * or a scheme and host", origin)
+		}
+	}
+	for _, proxy := range config.TrustedProxies {
+		_, _, err := net.ParseCIDR(proxy)
+		if err != nil && net.ParseIP(proxy) == nil {
+			problem("Trusted proxy %q is not an IP address or CIDR range", proxy)
+		}
+	}
+	if config.MetricsAddress != "" && config.MetricsAddress == config.ListenAddress {
+		problem("MetricsAddress must differ from ListenAddress")
+	}
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		problem("TLSCertFile and TLSKeyFile go together")
+	}
+	switch config.LogLevel {
+	case "", "debug", "info", "warn", "error":
+	default:
+		problem("LogLevel %q is not debug, info, warn or error", config.LogLevel)
+	}
+	switch config.LogFormat {
+	case "", "json", "console":
+	default:
+		problem("LogFormat %q is not json or console", config.LogFormat)
+	}
+	switch config.TraceExporter {
+	case "", "none", "stdout", "otlp":
+	default:
+		problem("TraceExporter %q is not stdout, otlp or none", config.TraceExporter)
+	}
+
+	fields := reflect.ValueOf(config)
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Type().Field(i).Name
+		if fields.Field(i).Kind() == reflect.Int && fields.Field(i).Int() < 0 &&
+			!(name == "StatementCacheSize" && config.StatementCacheSize == -1) {
+			problem("%s cannot be negative", name)
+		}
+	}
+	for route, seconds := range config.RouteTimeouts {
+		if seconds < 0 {
+			problem("Timeout of %s cannot be negative", route)
+		}
+	}
+	if config.MaxOpenConns != 0 && config.MaxIdleConns > config.MaxOpenConns {
+		problem("MaxIdleConns cannot exceed MaxOpenConns")
+	}
+
+	if len(problems) != 0 {
+		return fmt.Errorf("Invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package infrastructure
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// NewLogger writes log lines of the level and above to out, as JSON or as
+// console (key=value) lines. An empty level is info, an empty format console.
+func NewLogger(level, format string, out io.Writer) (*slog.Logger, error) {
+	var leveler slog.Level
+	if level != "" {
+		err := leveler.UnmarshalText([]byte(level))
+		if err != nil {
+			return nil, fmt.Errorf("Log level must be debug, info, warn or error")
+		}
+	}
+	options := &slog.HandlerOptions{Level: leveler}
+
+	switch strings.ToLower(format) {
+	case "json":
+		return slog.New(slog.NewJSONHandler(out, options)), nil
+	case "", "console":
+		return slog.New(slog.NewTextHandler(out, options)), nil
+	}
+	return nil, fmt.Errorf("Log format must be json or console")
+}
@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"time"
+
+	"game-tracker/interfaces"
+)
+
+var (
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "gametracker",
+		Name:      "db_query_duration_seconds",
+		Help:      "Time taken by database statements, by repository and operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"repo", "operation"})
+	queryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gametracker",
+		Name:      "db_query_errors_total",
+		Help:      "Database statements that failed, by repository and operation.",
+	}, []string{"repo", "operation"})
+	actionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gametracker",
+		Name:      "actions_total",
+		Help:      "Actions done in the application, like users created or games added.",
+	}, []string{"action"})
+)
+
+// MeasuredDbHandler times the statements a repository runs through its
+// handler and counts the failed ones
+type MeasuredDbHandler struct {
+	Handler interfaces.DbHandler
+	Repo    string
+}
+
+func NewMeasuredDbHandler(handler interfaces.DbHandler, repo string) MeasuredDbHandler {
+	return MeasuredDbHandler{Handler: handler, Repo: repo}
+}
+
+func (handler MeasuredDbHandler) Execute(ctx context.Context, statement string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	res, err := handler.Handler.Execute(ctx, statement, args...)
+	handler.observe("execute", start, err)
+	return res, err
+}
+
+func (handler MeasuredDbHandler) Query(ctx context.Context, statement string, args ...interface{}) (interfaces.Row, error) {
+	start := time.Now()
+	row, err := handler.Handler.Query(ctx, statement, args...)
+	handler.observe("query", start, err)
+	return row, err
+}
+
+func (handler MeasuredDbHandler) QueryRow(ctx context.Context, statement string, args ...interface{}) (int, error) {
+	start := time.Now()
+	id, err := handler.Handler.QueryRow(ctx, statement, args...)
+	handler.observe("query_row", start, err)
+	return id, err
+}
+
+// Transaction measures the whole transaction, and each of its statements
+func (handler MeasuredDbHandler) Transaction(ctx context.Context, fn func(tx interfaces.DbHandler) error) error {
+	start := time.Now()
+	err := handler.Handler.Transaction(ctx, func(tx interfaces.DbHandler) error {
+		return fn(MeasuredDbHandler{Handler: tx, Repo: handler.Repo})
+	})
+	handler.observe("transaction", start, err)
+	return err
+}
+
+func (handler MeasuredDbHandler) observe(operation string, start time.Time, err error) {
+	queryDuration.WithLabelValues(handler.Repo, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		queryErrors.WithLabelValues(handler.Repo, operation).Inc()
+	}
+}
+
+// RegisterDbStats exposes the connection pool statistics of the handler
+func RegisterDbStats(handler *PostgresqlHandler, dbName string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(handler.Conn, dbName))
+}
+
+// ActionCounter counts the actions of the use cases
+type ActionCounter struct{}
+
+func (counter ActionCounter) Count(action string) {
+	actionsTotal.WithLabelValues(action).Inc()
+}
@@ -1,23 +1,146 @@
 package infrastructure
 
 import (
+	"context"
 	"database/sql"
-	_ "github.com/lib/pq"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
 
 	"game-tracker/interfaces"
 )
 
 type PostgresqlHandler struct {
-	Conn *sql.DB
+	Conn       *sql.DB
+	statements *statementCache
 }
 
-func (handler *PostgresqlHandler) Execute(statement string, args ...interface{}) (sql.Result, error) {
-	res, err := handler.Conn.Exec(statement, args...)
-	return res, err
+// PoolOptions tune the connection pool of a handler, zero values keeping the
+// defaults of database/sql
+type PoolOptions struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	StatementCache  int //Statements kept prepared, none when 0
 }
 
-func (handler *PostgresqlHandler) Query(statement string, args ...interface{}) (interfaces.Row, error) {
-	rows, err := handler.Conn.Query(statement, args...)
+func (handler *PostgresqlHandler) Execute(ctx context.Context, statement string, args ...interface{}) (sql.Result, error) {
+	var res sql.Result
+	var err error
+	if stmt := handler.statements.prepared(ctx, handler.Conn, statement); stmt != nil {
+		res, err = stmt.ExecContext(ctx, args...)
+	} else {
+		res, err = handler.Conn.ExecContext(ctx, statement, args...)
+	}
+	return res, statementError(err)
+}
+
+func (handler *PostgresqlHandler) Query(ctx context.Context, statement string, args ...interface{}) (interfaces.Row, error) {
+	var rows *sql.Rows
+	var err error
+	if stmt := handler.statements.prepared(ctx, handler.Conn, statement); stmt != nil {
+		rows, err = stmt.QueryContext(ctx, args...)
+	} else {
+		rows, err = handler.Conn.QueryContext(ctx, statement, args...)
+	}
+	if err != nil {
+		return PostgresqlRow{}, err
+	}
+	r := PostgresqlRow{Rows: rows}
+	return r, nil
+}
+
+func (handler *PostgresqlHandler) QueryRow(ctx context.Context, statement string, args ...interface{}) (int, error) {
+	var id int
+	var err error
+	if stmt := handler.statements.prepared(ctx, handler.Conn, statement); stmt != nil {
+		err = stmt.QueryRowContext(ctx, args...).Scan(&id)
+	} else {
+		err = handler.Conn.QueryRowContext(ctx, statement, args...).Scan(&id)
+	}
+	return id, statementError(err)
+}
+
+func (handler *PostgresqlHandler) Transaction(ctx context.Context, fn func(tx interfaces.DbHandler) error) error {
+	tx, err := handler.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = fn(PostgresqlTx{Tx: tx, statements: handler.statements})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (handler *PostgresqlHandler) Ping(ctx context.Context) error {
+	return handler.Conn.PingContext(ctx)
+}
+
+// WarmUp opens up to conns connections of the pool ahead of the first
+// requests, pinging each. They stay open as idle connections. No more than
+// the pool can open are asked for, the last would wait for the timeout.
+func (handler *PostgresqlHandler) WarmUp(ctx context.Context, conns int) error {
+	if maxOpen := handler.Conn.Stats().MaxOpenConnections; maxOpen != 0 && conns > maxOpen {
+		conns = maxOpen
+	}
+	opened := []*sql.Conn{}
+	defer func() {
+		for _, conn := range opened {
+			conn.Close()
+		}
+	}()
+	for len(opened) < conns {
+		conn, err := handler.Conn.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		opened = append(opened, conn)
+		err = conn.PingContext(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the prepared statements and the connections of the pool, once
+// no statement runs anymore
+func (handler *PostgresqlHandler) Close() error {
+	handler.statements.close()
+	return handler.Conn.Close()
+}
+
+// PostgresqlTx runs the statements already prepared by its handler as such,
+// others unprepared. Preparing would wait for a second connection while the
+// transaction holds one.
+type PostgresqlTx struct {
+	Tx         *sql.Tx
+	statements *statementCache
+}
+
+func (handler PostgresqlTx) Execute(ctx context.Context, statement string, args ...interface{}) (sql.Result, error) {
+	var res sql.Result
+	var err error
+	if stmt, _ := handler.statements.lookup(statement); stmt != nil {
+		res, err = handler.Tx.StmtContext(ctx, stmt).ExecContext(ctx, args...)
+	} else {
+		res, err = handler.Tx.ExecContext(ctx, statement, args...)
+	}
+	return res, statementError(err)
+}
+
+func (handler PostgresqlTx) Query(ctx context.Context, statement string, args ...interface{}) (interfaces.Row, error) {
+	var rows *sql.Rows
+	var err error
+	if stmt, _ := handler.statements.lookup(statement); stmt != nil {
+		rows, err = handler.Tx.StmtContext(ctx, stmt).QueryContext(ctx, args...)
+	} else {
+		rows, err = handler.Tx.QueryContext(ctx, statement, args...)
+	}
 	if err != nil {
 		return PostgresqlRow{}, err
 	}
@@ -25,10 +148,30 @@ func (handler *PostgresqlHandler) Query(statement string, args ...interface{}) (
 	return r, nil
 }
 
-func (handler *PostgresqlHandler) QueryRow(statement string, args ...interface{}) (int, error) {
+func (handler PostgresqlTx) QueryRow(ctx context.Context, statement string, args ...interface{}) (int, error) {
 	var id int
-	err := handler.Conn.QueryRow(statement, args...).Scan(&id)
-	return id, err
+	var err error
+	if stmt, _ := handler.statements.lookup(statement); stmt != nil {
+		err = handler.Tx.StmtContext(ctx, stmt).QueryRowContext(ctx, args...).Scan(&id)
+	} else {
+		err = handler.Tx.QueryRowContext(ctx, statement, args...).Scan(&id)
+	}
+	return id, statementError(err)
+}
+
+// statementError marks the errors of broken unique constraints for the
+// repositories, which cannot know about the driver
+func statementError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+		return fmt.Errorf("%w: %v", interfaces.ErrUniqueViolation, err)
+	}
+	return err
+}
+
+// Transaction inside a transaction joins it
+func (handler PostgresqlTx) Transaction(ctx context.Context, fn func(tx interfaces.DbHandler) error) error {
+	return fn(handler)
 }
 
 type PostgresqlRow struct {
@@ -47,12 +190,22 @@ func (r PostgresqlRow) Close() error {
 	return r.Rows.Close()
 }
 
-func NewPostgresqlHandler(dbfileAdr string) (*PostgresqlHandler, error) {
+func NewPostgresqlHandler(dbfileAdr string, options PoolOptions) (*PostgresqlHandler, error) {
 	conn, err := sql.Open("postgres", dbfileAdr)
 	if err != nil {
 		return new(PostgresqlHandler), err
 	}
+	conn.SetMaxOpenConns(options.MaxOpenConns)
+	if options.MaxIdleConns != 0 {
+		conn.SetMaxIdleConns(options.MaxIdleConns)
+	}
+	conn.SetConnMaxLifetime(options.ConnMaxLifetime)
+	conn.SetConnMaxIdleTime(options.ConnMaxIdleTime)
+
 	postgresqlHandler := new(PostgresqlHandler)
 	postgresqlHandler.Conn = conn
+	if options.StatementCache > 0 {
+		postgresqlHandler.statements = newStatementCache(options.StatementCache)
+	}
 	return postgresqlHandler, nil
 }
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// statementCache keeps the statements of a handler prepared after their first
+// run, up to size statements. The repositories only run constant statements,
+// so the cache ends up holding all the hot ones.
+type statementCache struct {
+	size       int
+	mutex      sync.RWMutex
+	statements map[string]*sql.Stmt
+}
+
+func newStatementCache(size int) *statementCache {
+	return &statementCache{size: size, statements: make(map[string]*sql.Stmt)}
+}
+
+// prepared finds the statement prepared, or prepares it while the cache has
+// room. It is nil when the statement is to run unprepared.
+func (cache *statementCache) prepared(ctx context.Context, conn *sql.DB, statement string) *sql.Stmt {
+	stmt, full := cache.lookup(statement)
+	if stmt != nil || full {
+		return stmt
+	}
+	// Failed prepares run unprepared, to fail with the error of the run
+	stmt, err := conn.PrepareContext(ctx, statement)
+	if err != nil {
+		return nil
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cached, ok := cache.statements[statement]; ok {
+		stmt.Close()
+		return cached
+	}
+	if len(cache.statements) >= cache.size {
+		stmt.Close()
+		return nil
+	}
+	cache.statements[statement] = stmt
+	return stmt
+}
+
+// lookup finds the statement prepared, telling if the cache is full
+func (cache *statementCache) lookup(statement string) (*sql.Stmt, bool) {
+	if cache == nil {
+		return nil, true
+	}
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	return cache.statements[statement], len(cache.statements) >= cache.size
+}
+
+func (cache *statementCache) close() {
+	if cache == nil {
+		return
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	for statement, stmt := range cache.statements {
+		stmt.Close()
+		delete(cache.statements, statement)
+	}
+}
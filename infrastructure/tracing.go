@@ -0,0 +1,126 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"game-tracker/interfaces"
+)
+
+// SetUpTracing installs the tracer provider exporting spans to stdout or to
+// an OTLP collector over HTTP at endpoint, localhost:4318 when empty. With no
+// exporter or "none" spans are dropped. The returned function flushes the
+// spans left before exiting.
+func SetUpTracing(exporter, endpoint string) (func(ctx context.Context) error, error) {
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch exporter {
+	case "", "none":
+		return func(ctx context.Context) error { return nil }, nil
+	case "stdout":
+		spanExporter, err = stdouttrace.New()
+	case "otlp":
+		options := []otlptracehttp.Option{otlptracehttp.WithInsecure()}
+		if endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(endpoint))
+		}
+		spanExporter, err = otlptracehttp.New(context.Background(), options...)
+	default:
+		return nil, fmt.Errorf("Unknown trace exporter %q, use stdout, otlp or none", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName("game-tracker"))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// SpanTracer starts the spans of the use cases
+type SpanTracer struct{}
+
+func (tracer SpanTracer) Start(ctx context.Context, name string) (context.Context, func()) {
+	ctx, span := otel.Tracer("game-tracker/usecases").Start(ctx, name)
+	return ctx, func() { span.End() }
+}
+
+// TracedDbHandler starts a span for every statement a repository runs
+// through its handler, under the span of the use case calling it
+type TracedDbHandler struct {
+	Handler interfaces.DbHandler
+	Repo    string
+}
+
+func NewTracedDbHandler(handler interfaces.DbHandler, repo string) TracedDbHandler {
+	return TracedDbHandler{Handler: handler, Repo: repo}
+}
+
+func (handler TracedDbHandler) Execute(ctx context.Context, statement string, args ...interface{}) (sql.Result, error) {
+	ctx, span := handler.start(ctx, "Execute", statement)
+	res, err := handler.Handler.Execute(ctx, statement, args...)
+	endStatement(span, err)
+	return res, err
+}
+
+func (handler TracedDbHandler) Query(ctx context.Context, statement string, args ...interface{}) (interfaces.Row, error) {
+	ctx, span := handler.start(ctx, "Query", statement)
+	row, err := handler.Handler.Query(ctx, statement, args...)
+	endStatement(span, err)
+	return row, err
+}
+
+func (handler TracedDbHandler) QueryRow(ctx context.Context, statement string, args ...interface{}) (int, error) {
+	ctx, span := handler.start(ctx, "QueryRow", statement)
+	id, err := handler.Handler.QueryRow(ctx, statement, args...)
+	endStatement(span, err)
+	return id, err
+}
+
+// Transaction spans the whole transaction, each of its statements gets its
+// own span too
+func (handler TracedDbHandler) Transaction(ctx context.Context, fn func(tx interfaces.DbHandler) error) error {
+	ctx, span := handler.start(ctx, "Transaction", "")
+	err := handler.Handler.Transaction(ctx, func(tx interfaces.DbHandler) error {
+		return fn(TracedDbHandler{Handler: tx, Repo: handler.Repo})
+	})
+	endStatement(span, err)
+	return err
+}
+
+func (handler TracedDbHandler) start(ctx context.Context, operation, statement string) (context.Context, trace.Span) {
+	attributes := []attribute.KeyValue{semconv.DBSystemPostgreSQL,
+		attribute.String("db.repository", handler.Repo)}
+	if statement != "" {
+		attributes = append(attributes, semconv.DBStatement(statement))
+	}
+	return otel.Tracer("game-tracker/interfaces").Start(ctx, handler.Repo+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+// endStatement ends the span of a statement, marking it failed unless it just
+// found nothing
+func endStatement(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
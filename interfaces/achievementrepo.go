@@ -0,0 +1,141 @@
+package interfaces
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"game-tracker/usecases"
+)
+
+type DbAchievementRepo DbRepo
+
+func NewDbAchievementRepo(dbHandlers map[string]DbHandler) *DbAchievementRepo {
+	dbAchievementRepo := new(DbAchievementRepo)
+	dbAchievementRepo.dbHandlers = dbHandlers
+	dbAchievementRepo.dbHandler = dbHandlers["DbAchievementRepo"]
+	return dbAchievementRepo
+}
+
+func (repo DbAchievementRepo) Store(ctx context.Context, achievement usecases.Achievement) (int, error) {
+	id, err := repo.dbHandler.QueryRow(ctx, `INSERT INTO achievements (game_id, name, description,
+		points, hidden) VALUES ($1, $2, $3, $4, $5) RETURNING id`, achievement.GameId,
+		achievement.Name, achievement.Description, achievement.Points, achievement.Hidden)
+	return id, err
+}
+
+func (repo DbAchievementRepo) FindById(ctx context.Context, id int) (usecases.Achievement, error, int) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT game_id, name, description, points, hidden
+		FROM achievements WHERE id = $1 LIMIT 1`, id)
+	if err != nil {
+		return usecases.Achievement{}, err, 500
+	}
+	achievement := usecases.Achievement{Id: id}
+	defer row.Close()
+	row.Next()
+	err = row.Scan(&achievement.GameId, &achievement.Name, &achievement.Description,
+		&achievement.Points, &achievement.Hidden)
+	if err != nil {
+		return usecases.Achievement{}, err, 404
+	}
+	return achievement, nil, 200
+}
+
+func (repo DbAchievementRepo) FindByGame(ctx context.Context, gameId int) ([]usecases.Achievement, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id, name, description, points, hidden
+		FROM achievements WHERE game_id = $1 ORDER BY id`, gameId)
+	if err != nil {
+		return nil, err
+	}
+	var achievements []usecases.Achievement
+	defer row.Close()
+	for row.Next() {
+		achievement := usecases.Achievement{GameId: gameId}
+		err = row.Scan(&achievement.Id, &achievement.Name, &achievement.Description,
+			&achievement.Points, &achievement.Hidden)
+		if err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, achievement)
+	}
+	return achievements, nil
+}
+
+func (repo DbAchievementRepo) Unlock(ctx context.Context, playerId, achievementId int, unlockedAt time.Time) (error, int) {
+	res, err := repo.dbHandler.Execute(ctx, `INSERT INTO player_achievements (player_id, achievement_id,
+		unlocked_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, playerId, achievementId, unlockedAt)
+	if err != nil {
+		return err, 500
+	}
+	unlocked, err := res.RowsAffected()
+	if err != nil {
+		return err, 500
+	}
+	if unlocked == 0 {
+		err = fmt.Errorf("Achievement already unlocked")
+		return err, 400
+	}
+	return nil, 200
+}
+
+func (repo DbAchievementRepo) FindUnlocked(ctx context.Context, playerId, gameId int) (map[int]time.Time, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT player_achievements.achievement_id,
+		player_achievements.unlocked_at FROM player_achievements
+		JOIN achievements ON achievements.id = player_achievements.achievement_id
+		WHERE player_achievements.player_id=$1 AND achievements.game_id=$2`, playerId, gameId)
+	if err != nil {
+		return nil, err
+	}
+	unlocked := make(map[int]time.Time)
+	var (
+		achievementId int
+		unlockedAt    time.Time
+	)
+	defer row.Close()
+	for row.Next() {
+		err = row.Scan(&achievementId, &unlockedAt)
+		if err != nil {
+			return nil, err
+		}
+		unlocked[achievementId] = unlockedAt
+	}
+	return unlocked, nil
+}
+
+func (repo DbAchievementRepo) FindAllUnlocked(ctx context.Context, playerId int) ([]usecases.PlayerAchievement, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT achievements.id, achievements.game_id,
+		achievements.name, achievements.description, achievements.points, achievements.hidden,
+		player_achievements.unlocked_at FROM player_achievements
+		JOIN achievements ON achievements.id = player_achievements.achievement_id
+		WHERE player_achievements.player_id=$1 ORDER BY player_achievements.unlocked_at`, playerId)
+	if err != nil {
+		return nil, err
+	}
+	var achievements []usecases.PlayerAchievement
+	defer row.Close()
+	for row.Next() {
+		achievement := usecases.PlayerAchievement{Unlocked: true}
+		err = row.Scan(&achievement.Id, &achievement.GameId, &achievement.Name,
+			&achievement.Description, &achievement.Points, &achievement.Hidden,
+			&achievement.UnlockedAt)
+		if err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, achievement)
+	}
+	return achievements, nil
+}
+
+func (repo DbAchievementRepo) GamerScore(ctx context.Context, playerId int) (int, error) {
+	score, err := repo.dbHandler.QueryRow(ctx, `SELECT COALESCE(SUM(achievements.points), 0)
+		FROM player_achievements
+		JOIN achievements ON achievements.id = player_achievements.achievement_id
+		WHERE player_achievements.player_id = $1 AND EXISTS (
+			SELECT 1 FROM gamesInLib
+			JOIN libraries ON libraries.id = gamesInLib.library_id
+			JOIN users ON users.id = libraries.user_id
+			WHERE users.player_id = player_achievements.player_id
+			AND gamesInLib.game_id = achievements.game_id
+			AND users.deleted_at IS NULL AND libraries.deleted_at IS NULL)`, playerId)
+	return score, err
+}
@@ -0,0 +1,46 @@
+package interfaces
+
+import (
+	"context"
+	"game-tracker/usecases"
+)
+
+type DbAuditRepo DbRepo
+
+func NewDbAuditRepo(dbHandlers map[string]DbHandler) *DbAuditRepo {
+	dbAuditRepo := new(DbAuditRepo)
+	dbAuditRepo.dbHandlers = dbHandlers
+	dbAuditRepo.dbHandler = dbHandlers["DbAuditRepo"]
+	return dbAuditRepo
+}
+
+func (repo DbAuditRepo) Store(ctx context.Context, entry usecases.AuditEntry) error {
+	_, err := repo.dbHandler.Execute(ctx, `INSERT INTO audit_log (action, actor_id, target_type,
+		target_id, detail, ip, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`, entry.Action,
+		entry.ActorId, entry.TargetType, entry.TargetId, entry.Detail, entry.Ip, entry.CreatedAt)
+	return err
+}
+
+func (repo DbAuditRepo) Find(ctx context.Context, filter usecases.AuditFilter) ([]usecases.AuditEntry, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id, action, actor_id, target_type, target_id,
+		detail, ip, created_at FROM audit_log
+		WHERE ($1 = 0 OR actor_id=$1) AND ($2 = '' OR action=$2)
+		AND created_at >= $3 AND created_at <= $4
+		ORDER BY created_at DESC, id DESC LIMIT NULLIF($5, 0)`, filter.ActorId, filter.Action,
+		filter.From, filter.To, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	var entries []usecases.AuditEntry
+	defer row.Close()
+	for row.Next() {
+		var entry usecases.AuditEntry
+		err = row.Scan(&entry.Id, &entry.Action, &entry.ActorId, &entry.TargetType,
+			&entry.TargetId, &entry.Detail, &entry.Ip, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
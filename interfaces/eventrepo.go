@@ -0,0 +1,82 @@
+package interfaces
+
+import (
+	"context"
+	"game-tracker/usecases"
+)
+
+type DbEventRepo DbRepo
+
+func NewDbEventRepo(dbHandlers map[string]DbHandler) *DbEventRepo {
+	dbEventRepo := new(DbEventRepo)
+	dbEventRepo.dbHandlers = dbHandlers
+	dbEventRepo.dbHandler = dbHandlers["DbEventRepo"]
+	return dbEventRepo
+}
+
+func (repo DbEventRepo) Store(ctx context.Context, event usecases.Event) error {
+	_, err := repo.dbHandler.Execute(ctx, `INSERT INTO events (kind, user_id, library_id, game_id,
+		created_at) VALUES ($1, $2, $3, $4, $5)`, event.Kind, event.UserId, event.LibraryId,
+		event.GameId, event.CreatedAt)
+	return err
+}
+
+// eventsWithOwners joins the events to the user and the library they
+// happened in, for eventsSeenBy to filter
+const eventsWithOwners = `SELECT events.id, events.kind, events.user_id, events.library_id,
+	events.game_id, games.name, events.created_at FROM events
+	JOIN games ON games.id = events.game_id
+	JOIN users ON users.id = events.user_id
+	JOIN libraries ON libraries.id = events.library_id`
+
+// eventsSeenBy keeps the events the viewer $1 sees: those of existing
+// profiles and libraries that are the viewer's own, public, or visible to
+// friends when the viewer is one. $2 is the accepted status of friendships,
+// $3 and $4 the friends and public visibilities.
+const eventsSeenBy = `users.deleted_at IS NULL AND libraries.deleted_at IS NULL
+	AND (users.id = $1 OR users.visibility IN ($3, $4) AND libraries.visibility IN ($3, $4)
+		AND (users.visibility = $4 AND libraries.visibility = $4 OR EXISTS (
+			SELECT 1 FROM friendships WHERE status=$2
+			AND ((user_id=$1 AND friend_id=users.id) OR (user_id=users.id AND friend_id=$1)))))`
+
+func (repo DbEventRepo) FindByUser(ctx context.Context, viewerId, userId, before, limit int) ([]usecases.Event, error) {
+	row, err := repo.dbHandler.Query(ctx, eventsWithOwners+`
+		WHERE `+eventsSeenBy+` AND events.user_id=$5 AND ($6 = 0 OR events.id < $6)
+		ORDER BY events.id DESC LIMIT NULLIF($7, 0)`, viewerId, usecases.FriendshipAccepted,
+		usecases.VisibilityFriends, usecases.VisibilityPublic, userId, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+	return scanEvents(row)
+}
+
+// FindFollowed finds the events of the users the follower follows, as the
+// follower sees them
+func (repo DbEventRepo) FindFollowed(ctx context.Context, followerId, before, limit int) ([]usecases.Event, error) {
+	row, err := repo.dbHandler.Query(ctx, eventsWithOwners+`
+		WHERE `+eventsSeenBy+`
+		AND events.user_id IN (SELECT followee_id FROM follows WHERE follower_id=$1)
+		AND ($5 = 0 OR events.id < $5)
+		ORDER BY events.id DESC LIMIT NULLIF($6, 0)`, followerId, usecases.FriendshipAccepted,
+		usecases.VisibilityFriends, usecases.VisibilityPublic, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+	return scanEvents(row)
+}
+
+func scanEvents(row Row) ([]usecases.Event, error) {
+	var events []usecases.Event
+	for row.Next() {
+		var event usecases.Event
+		err := row.Scan(&event.Id, &event.Kind, &event.UserId, &event.LibraryId, &event.GameId,
+			&event.GameName, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
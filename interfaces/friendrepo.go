@@ -0,0 +1,201 @@
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"game-tracker/usecases"
+)
+
+type DbFriendRepo DbRepo
+
+func NewDbFriendRepo(dbHandlers map[string]DbHandler) *DbFriendRepo {
+	dbFriendRepo := new(DbFriendRepo)
+	dbFriendRepo.dbHandlers = dbHandlers
+	dbFriendRepo.dbHandler = dbHandlers["DbFriendRepo"]
+	return dbFriendRepo
+}
+
+// Request stores a pending friendship, unless the two users already have one
+// either way
+func (repo DbFriendRepo) Request(ctx context.Context, friendship usecases.Friendship) (error, int) {
+	res, err := repo.dbHandler.Execute(ctx, `INSERT INTO friendships (user_id, friend_id, status,
+		requested_at) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`, friendship.UserId,
+		friendship.FriendId, friendship.Status, friendship.RequestedAt)
+	if err != nil {
+		return err, 500
+	}
+	stored, err := res.RowsAffected()
+	if err != nil {
+		return err, 500
+	}
+	if stored == 0 {
+		message := "Users #%d and #%d are already friends or asked to be"
+		return fmt.Errorf(message, friendship.UserId, friendship.FriendId), 409
+	}
+	return nil, 200
+}
+
+func (repo DbFriendRepo) Accept(ctx context.Context, userId, requesterId int, acceptedAt time.Time) (error, int) {
+	res, err := repo.dbHandler.Execute(ctx, `UPDATE friendships SET status=$3, accepted_at=$4
+		WHERE user_id=$1 AND friend_id=$2 AND status=$5`, requesterId, userId,
+		usecases.FriendshipAccepted, acceptedAt, usecases.FriendshipPending)
+	if err != nil {
+		return err, 500
+	}
+	accepted, err := res.RowsAffected()
+	if err != nil {
+		return err, 500
+	}
+	if accepted == 0 {
+		message := "User #%d has no friend request from user #%d"
+		return fmt.Errorf(message, userId, requesterId), 404
+	}
+	return nil, 200
+}
+
+func (repo DbFriendRepo) Remove(ctx context.Context, userId, otherUserId int) (error, int) {
+	res, err := repo.dbHandler.Execute(ctx, `DELETE FROM friendships
+		WHERE (user_id=$1 AND friend_id=$2) OR (user_id=$2 AND friend_id=$1)`,
+		userId, otherUserId)
+	if err != nil {
+		return err, 500
+	}
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return err, 500
+	}
+	if removed == 0 {
+		message := "Users #%d and #%d are not friends nor asked to be"
+		return fmt.Errorf(message, userId, otherUserId), 404
+	}
+	return nil, 200
+}
+
+func (repo DbFriendRepo) FindByUser(ctx context.Context, userId int) ([]usecases.Friendship, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT user_id, friend_id, status, requested_at,
+		accepted_at FROM friendships WHERE (user_id=$1 OR friend_id=$1)
+		AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)
+		AND friend_id IN (SELECT id FROM users WHERE deleted_at IS NULL)
+		ORDER BY requested_at`, userId)
+	if err != nil {
+		return nil, err
+	}
+	var friendships []usecases.Friendship
+	defer row.Close()
+	for row.Next() {
+		var friendship usecases.Friendship
+		var acceptedAt sql.NullTime
+		err = row.Scan(&friendship.UserId, &friendship.FriendId, &friendship.Status,
+			&friendship.RequestedAt, &acceptedAt)
+		if err != nil {
+			return nil, err
+		}
+		if acceptedAt.Valid {
+			friendship.AcceptedAt = &acceptedAt.Time
+		}
+		friendships = append(friendships, friendship)
+	}
+	return friendships, nil
+}
+
+func (repo DbFriendRepo) AreFriends(ctx context.Context, userId, otherUserId int) (bool, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT 1 FROM friendships
+		WHERE ((user_id=$1 AND friend_id=$2) OR (user_id=$2 AND friend_id=$1))
+		AND status=$3 LIMIT 1`, userId, otherUserId, usecases.FriendshipAccepted)
+	if err != nil {
+		return false, err
+	}
+	defer row.Close()
+	return row.Next(), nil
+}
+
+// FindOwningFriends finds the friends with the game in a library their
+// friends can see, from the ownership in gamesInLib
+func (repo DbFriendRepo) FindOwningFriends(ctx context.Context, userId, gameId int) ([]int, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT DISTINCT users.id FROM friendships
+		JOIN users ON users.id = CASE WHEN friendships.user_id=$1
+			THEN friendships.friend_id ELSE friendships.user_id END
+		JOIN libraries ON libraries.user_id = users.id
+		JOIN gamesInLib ON gamesInLib.library_id = libraries.id
+		WHERE (friendships.user_id=$1 OR friendships.friend_id=$1)
+		AND friendships.status=$3 AND gamesInLib.game_id=$2
+		AND users.deleted_at IS NULL AND users.visibility IN ($4, $5)
+		AND libraries.deleted_at IS NULL AND libraries.visibility IN ($4, $5)
+		ORDER BY users.id`, userId, gameId, usecases.FriendshipAccepted,
+		usecases.VisibilityFriends, usecases.VisibilityPublic)
+	if err != nil {
+		return nil, err
+	}
+	var userIds []int
+	defer row.Close()
+	for row.Next() {
+		var id int
+		err = row.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, id)
+	}
+	return userIds, nil
+}
+
+func (repo DbFriendRepo) Follow(ctx context.Context, follow usecases.Follow) error {
+	_, err := repo.dbHandler.Execute(ctx, `INSERT INTO follows (follower_id, followee_id,
+		followed_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, follow.FollowerId,
+		follow.FolloweeId, follow.FollowedAt)
+	return err
+}
+
+func (repo DbFriendRepo) Unfollow(ctx context.Context, followerId, followeeId int) (error, int) {
+	res, err := repo.dbHandler.Execute(ctx, `DELETE FROM follows
+		WHERE follower_id=$1 AND followee_id=$2`, followerId, followeeId)
+	if err != nil {
+		return err, 500
+	}
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return err, 500
+	}
+	if removed == 0 {
+		return fmt.Errorf("User #%d does not follow user #%d", followerId, followeeId), 404
+	}
+	return nil, 200
+}
+
+func (repo DbFriendRepo) FindFollowing(ctx context.Context, userId int) ([]usecases.Follow, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT follower_id, followee_id, followed_at FROM follows
+		WHERE follower_id=$1 AND followee_id IN (SELECT id FROM users WHERE deleted_at IS NULL)
+		ORDER BY followed_at`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+	return scanFollows(row)
+}
+
+func (repo DbFriendRepo) FindFollowers(ctx context.Context, userId int) ([]usecases.Follow, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT follower_id, followee_id, followed_at FROM follows
+		WHERE followee_id=$1 AND follower_id IN (SELECT id FROM users WHERE deleted_at IS NULL)
+		ORDER BY followed_at`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+	return scanFollows(row)
+}
+
+func scanFollows(row Row) ([]usecases.Follow, error) {
+	var follows []usecases.Follow
+	for row.Next() {
+		var follow usecases.Follow
+		err := row.Scan(&follow.FollowerId, &follow.FolloweeId, &follow.FollowedAt)
+		if err != nil {
+			return nil, err
+		}
+		follows = append(follows, follow)
+	}
+	return follows, nil
+}
@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+)
+
+// SchemaVersion is the migration the repositories are written against
+const SchemaVersion = 14
+
+// DbPinger tells if the database answers at all
+type DbPinger interface {
+	Ping(ctx context.Context) error
+}
+
+type DbHealthRepo struct {
+	pinger    DbPinger
+	dbHandler DbHandler
+}
+
+func NewDbHealthRepo(pinger DbPinger, dbHandlers map[string]DbHandler) *DbHealthRepo {
+	dbHealthRepo := new(DbHealthRepo)
+	dbHealthRepo.pinger = pinger
+	dbHealthRepo.dbHandler = dbHandlers["DbHealthRepo"]
+	return dbHealthRepo
+}
+
+func (repo DbHealthRepo) Ping(ctx context.Context) error {
+	return repo.pinger.Ping(ctx)
+}
+
+// SchemaVersion finds the last migration applied to the database
+func (repo DbHealthRepo) SchemaVersion(ctx context.Context) (int, error) {
+	return repo.dbHandler.QueryRow(ctx, `SELECT version FROM schema_version LIMIT 1`)
+}
@@ -0,0 +1,35 @@
+package interfaces
+
+import (
+	"log/slog"
+
+	"game-tracker/usecases"
+)
+
+type LoggerRepo struct {
+	logger *slog.Logger
+}
+
+func NewLoggerRepo(logger *slog.Logger) *LoggerRepo {
+	return &LoggerRepo{logger: logger}
+}
+
+func (repo LoggerRepo) Debug(message string, fields ...interface{}) {
+	repo.logger.Debug(message, fields...)
+}
+
+func (repo LoggerRepo) Info(message string, fields ...interface{}) {
+	repo.logger.Info(message, fields...)
+}
+
+func (repo LoggerRepo) Warn(message string, fields ...interface{}) {
+	repo.logger.Warn(message, fields...)
+}
+
+func (repo LoggerRepo) Error(message string, fields ...interface{}) {
+	repo.logger.Error(message, fields...)
+}
+
+func (repo LoggerRepo) With(fields ...interface{}) usecases.LoggerRepository {
+	return LoggerRepo{logger: repo.logger.With(fields...)}
+}
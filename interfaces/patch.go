@@ -0,0 +1,127 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+)
+
+const jsonApiMediaType = "application/vnd.api+json"
+
+// A PATCH body reduced to the attributes it changes and the version of the
+// resource it was written against. Bodies are either a JSON Merge Patch
+// (RFC 7386) of the attributes or a JSON:API document. The version comes from
+// the If-Match header, or else from the "version" member of the merge patch
+// or the meta of the JSON:API document.
+type patch struct {
+	attributes map[string]json.RawMessage
+	version    int
+}
+
+type jsonApiPatch struct {
+	Data struct {
+		Type       string                     `json:"type"`
+		Id         string                     `json:"id"`
+		Attributes map[string]json.RawMessage `json:"attributes"`
+		Meta       struct {
+			Version *int `json:"version"`
+		} `json:"meta"`
+	} `json:"data"`
+}
+
+func readPatch(c *gin.Context, resourceType string, id int) (patch, error) {
+	var p patch
+	var version *int
+	if strings.HasPrefix(c.ContentType(), jsonApiMediaType) {
+		document := jsonApiPatch{}
+		err := json.NewDecoder(c.Request.Body).Decode(&document)
+		if err != nil {
+			return patch{}, err
+		}
+		if document.Data.Type != resourceType || document.Data.Id != strconv.Itoa(id) {
+			return patch{}, fmt.Errorf("Document does not describe %s #%d", resourceType, id)
+		}
+		p.attributes = document.Data.Attributes
+		version = document.Data.Meta.Version
+	} else {
+		err := json.NewDecoder(c.Request.Body).Decode(&p.attributes)
+		if err != nil {
+			return patch{}, err
+		}
+		if raw, ok := p.attributes["version"]; ok {
+			version = new(int)
+			err = json.Unmarshal(raw, version)
+			if err != nil {
+				return patch{}, fmt.Errorf("Version must be a number")
+			}
+			delete(p.attributes, "version")
+		}
+	}
+
+	if etag := c.GetHeader("If-Match"); etag != "" {
+		headerVersion, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(etag, "W/"), `"`))
+		if err != nil {
+			return patch{}, fmt.Errorf("If-Match must be a version")
+		}
+		version = &headerVersion
+	}
+	if version == nil {
+		return patch{}, fmt.Errorf("The version to edit is required (If-Match header)")
+	}
+	p.version = *version
+	return p, nil
+}
+
+// only refuses attributes that cannot be patched.
+func (p patch) only(names ...string) error {
+	for name := range p.attributes {
+		allowed := false
+		for _, allowedName := range names {
+			allowed = allowed || name == allowedName
+		}
+		if !allowed {
+			return fmt.Errorf("Attribute '%s' cannot be edited", name)
+		}
+	}
+	return nil
+}
+
+// string reads an attribute, nil when absent. A null value clears the attribute
+// unless it is required.
+func (p patch) string(name string, required bool) (*string, error) {
+	raw, ok := p.attributes[name]
+	if !ok {
+		return nil, nil
+	}
+	if string(raw) == "null" {
+		if required {
+			return nil, fmt.Errorf("Attribute '%s' cannot be removed", name)
+		}
+		return new(string), nil
+	}
+	value := new(string)
+	err := json.Unmarshal(raw, value)
+	if err != nil {
+		return nil, fmt.Errorf("Attribute '%s' must be a string", name)
+	}
+	return value, nil
+}
+
+func (p patch) number(name string) (*float64, error) {
+	raw, ok := p.attributes[name]
+	if !ok {
+		return nil, nil
+	}
+	value := new(float64)
+	err := json.Unmarshal(raw, value)
+	if err != nil || string(raw) == "null" {
+		return nil, fmt.Errorf("Attribute '%s' must be a number", name)
+	}
+	return value, nil
+}
+
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
@@ -1,17 +1,28 @@
 package interfaces
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"game-tracker/domain"
 	"game-tracker/usecases"
 )
 
+// ErrUniqueViolation is wrapped by the errors of statements breaking a unique
+// constraint, which repositories answer with 409
+var ErrUniqueViolation = errors.New("Unique constraint violated")
+
 type DbHandler interface {
-	Execute(statement string, args ...interface{}) (sql.Result, error)
-	Query(statement string, args ...interface{}) (Row, error)
-	QueryRow(statement string, args ...interface{}) (int, error)
+	Execute(ctx context.Context, statement string, args ...interface{}) (sql.Result, error)
+	Query(ctx context.Context, statement string, args ...interface{}) (Row, error)
+	QueryRow(ctx context.Context, statement string, args ...interface{}) (int, error)
+	// Transaction runs fn against a handler bound to a single transaction,
+	// committed if fn returns nil and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(tx DbHandler) error) error
 }
 
 type Row interface {
@@ -29,7 +40,6 @@ type DbUserRepo DbRepo
 type DbPlayerRepo DbRepo
 type DbLibraryRepo DbRepo
 type DbGameRepo DbRepo
-type LoggerRepo DbRepo
 
 func NewDbUserRepo(dbHandlers map[string]DbHandler) *DbUserRepo {
 	dbUserRepo := new(DbUserRepo)
@@ -38,52 +48,132 @@ func NewDbUserRepo(dbHandlers map[string]DbHandler) *DbUserRepo {
 	return dbUserRepo
 }
 
-func (repo DbUserRepo) Store(user usecases.User) (int, error) {
-	id, err := repo.dbHandler.QueryRow(`INSERT INTO users (user_name, player_id, personal_info)
-		VALUES ($1, $2, $3) RETURNING id`, user.Name, user.Player.Id, user.PersonalInfo)
+// Store saves the user and its login at once, under the player holding the
+// player name, created when needed, whatever player id the user had.
+func (repo DbUserRepo) Store(ctx context.Context, user usecases.User, password string) (int, error) {
+	var id int
+	err := repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		playerRepo := DbPlayerRepo{dbHandlers: repo.dbHandlers, dbHandler: tx}
+		playerId, err := playerRepo.Store(ctx, user.Player)
+		if err != nil {
+			return err
+		}
+		id, err = tx.QueryRow(ctx, `INSERT INTO users (user_name, player_id, personal_info)
+			VALUES ($1, $2, $3) RETURNING id`, user.Name, playerId, user.PersonalInfo)
+		if err != nil {
+			return err
+		}
+		userRepo := DbUserRepo{dbHandlers: repo.dbHandlers, dbHandler: tx}
+		return userRepo.AddLoginInfo(ctx, user.Name, password)
+	})
+	return id, err
+}
+
+// Remove marks the user and their libraries as deleted at the same time, for
+// Restore to bring back only those libraries.
+func (repo DbUserRepo) Remove(ctx context.Context, user usecases.User, deletedAt time.Time) error {
+	return repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		_, err := tx.Execute(ctx, `UPDATE libraries SET deleted_at=$2
+			WHERE user_id=$1 AND deleted_at IS NULL`, user.Id, deletedAt)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Execute(ctx, `UPDATE users SET deleted_at=$2 WHERE id=$1`, user.Id, deletedAt)
+		return err
+	})
+}
+
+func (repo DbUserRepo) FindDeleted(ctx context.Context, id int) (usecases.User, time.Time, error, int) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT user_name, player_id, deleted_at FROM users
+		WHERE id=$1 AND deleted_at IS NOT NULL LIMIT 1`, id)
 	if err != nil {
-		return 0, err
+		return usecases.User{}, time.Time{}, err, 500
 	}
-
-	playerRepo := NewDbPlayerRepo(repo.dbHandlers)
-	err = playerRepo.Store(user.Player)
+	user := usecases.User{Id: id}
+	var deletedAt time.Time
+	defer row.Close()
+	if !row.Next() {
+		return usecases.User{}, time.Time{}, fmt.Errorf("User #%d is not deleted", id), 404
+	}
+	err = row.Scan(&user.Name, &user.Player.Id, &deletedAt)
 	if err != nil {
-		return id, err
+		return usecases.User{}, time.Time{}, err, 500
 	}
-	return id, nil
+	return user, deletedAt, nil, 200
 }
 
-func (repo DbUserRepo) Remove(user usecases.User) error {
-	_, err := repo.dbHandler.Execute(`DELETE FROM users WHERE id=$1`, user.Id)
-	return err
+func (repo DbUserRepo) Restore(ctx context.Context, user usecases.User, deletedAt time.Time) error {
+	return repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		_, err := tx.Execute(ctx, `UPDATE libraries SET deleted_at=NULL
+			WHERE user_id=$1 AND deleted_at=$2`, user.Id, deletedAt)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Execute(ctx, `UPDATE users SET deleted_at=NULL WHERE id=$1`, user.Id)
+		return err
+	})
 }
 
-func (repo DbUserRepo) FindById(id int) (usecases.User, error, int) {
-	row, err := repo.dbHandler.Query(`SELECT user_name, player_id, personal_info FROM users
-		WHERE id = $1 LIMIT 1`, id)
+// Purge removes for good the users deleted before the given time, with their
+// login and every library they had.
+func (repo DbUserRepo) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	var purged int64
+	err := repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		statements := []string{
+			`DELETE FROM loginInfo WHERE username IN (
+				SELECT user_name FROM users WHERE deleted_at < $1)`,
+			`DELETE FROM gamesInLib WHERE library_id IN (SELECT libraries.id FROM libraries
+				JOIN users ON users.id = libraries.user_id WHERE users.deleted_at < $1)`,
+			`DELETE FROM libraries WHERE user_id IN (
+				SELECT id FROM users WHERE deleted_at < $1)`,
+		}
+		for _, statement := range statements {
+			_, err := tx.Execute(ctx, statement, deletedBefore)
+			if err != nil {
+				return err
+			}
+		}
+		res, err := tx.Execute(ctx, `DELETE FROM users WHERE deleted_at < $1`, deletedBefore)
+		if err != nil {
+			return err
+		}
+		purged, err = res.RowsAffected()
+		return err
+	})
+	return int(purged), err
+}
+
+func (repo DbUserRepo) FindById(ctx context.Context, id int) (usecases.User, error, int) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT user_name, player_id, personal_info, visibility,
+		is_admin, version FROM users WHERE id = $1 AND deleted_at IS NULL LIMIT 1`, id)
 	if err != nil {
 		return usecases.User{}, err, 500
 	}
 	var userName string
 	var playerId int
 	var personalInfo string
+	var visibility string
+	var admin bool
+	var version int
 	defer row.Close()
 	row.Next()
-	err = row.Scan(&userName, &playerId, &personalInfo)
+	err = row.Scan(&userName, &playerId, &personalInfo, &visibility, &admin, &version)
 	if err != nil {
 		return usecases.User{}, err, 404
 	}
 
 	playerRepo := NewDbPlayerRepo(repo.dbHandlers)
-	player, err, code := playerRepo.FindById(playerId)
+	player, err, code := playerRepo.FindById(ctx, playerId)
 	if err != nil {
 		return usecases.User{}, err, code
 	}
 
-	user := usecases.User{Id: id, Name: userName, Player: player, PersonalInfo: personalInfo}
+	user := usecases.User{Id: id, Name: userName, Player: player, PersonalInfo: personalInfo,
+		Visibility: visibility, Admin: admin, Version: version}
 
 	var libraryId int
-	row, err = repo.dbHandler.Query(`SELECT id FROM libraries WHERE user_id = $1`, id)
+	row, err = repo.dbHandler.Query(ctx, `SELECT id FROM libraries
+		WHERE user_id = $1 AND deleted_at IS NULL`, id)
 	if err != nil {
 		return user, err, 500
 	}
@@ -98,21 +188,21 @@ func (repo DbUserRepo) FindById(id int) (usecases.User, error, int) {
 	return user, nil, 200
 }
 
-func (repo DbUserRepo) UserExisted(userName string) (bool, error) {
-	row, err := repo.dbHandler.Query(`SELECT user_name FROM users
+func (repo DbUserRepo) UserExisted(ctx context.Context, userName string) (bool, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT user_name FROM users
 		WHERE user_name=$1 LIMIT 1`, userName)
 	defer row.Close()
 	return row.Next(), err
 }
 
-func (repo DbUserRepo) StoreInfo(user usecases.User, info string) error {
-	_, err := repo.dbHandler.Execute(`UPDATE users SET personal_info=$1
+func (repo DbUserRepo) StoreInfo(ctx context.Context, user usecases.User, info string) error {
+	_, err := repo.dbHandler.Execute(ctx, `UPDATE users SET personal_info=$1
 		WHERE id=$2`, info, user.Id)
 	return err
 }
 
-func (repo DbUserRepo) LoadInfo(user usecases.User) (string, error) {
-	row, err := repo.dbHandler.Query(`SELECT personal_info FROM users WHERE id=$1`, user.Id)
+func (repo DbUserRepo) LoadInfo(ctx context.Context, user usecases.User) (string, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT personal_info FROM users WHERE id=$1`, user.Id)
 	if err != nil {
 		return "", err
 	}
@@ -123,26 +213,128 @@ func (repo DbUserRepo) LoadInfo(user usecases.User) (string, error) {
 	return info, err
 }
 
-func (repo DbUserRepo) PlayerNameMatchesId(user usecases.User) (bool, error) {
+// PlayerNameMatchesId tells if the player of the user is consistent: an
+// existing name comes with its own id, a new name with an id no player has.
+// A player id of 0 always matches, the player is then found by name.
+func (repo DbUserRepo) PlayerNameMatchesId(ctx context.Context, user usecases.User) (bool, error) {
+	if user.Player.Id == 0 {
+		return true, nil
+	}
 	playerRepo := NewDbPlayerRepo(repo.dbHandlers)
-	existed, err := playerRepo.playerExisted(user.Player.Name)
+	existed, err := playerRepo.playerExisted(ctx, user.Player.Name)
 	if err != nil {
 		return false, err
 	}
 	if existed {
-		match, err := playerRepo.nameMatchesId(user.Player.Name, user.Player.Id)
+		return playerRepo.NameMatchesId(ctx, user.Player.Name, user.Player.Id)
+	}
+	_, err, code := playerRepo.FindById(ctx, user.Player.Id)
+	if code == 404 {
+		return true, nil
+	}
+	return false, err
+}
+
+func (repo DbUserRepo) Update(ctx context.Context, user usecases.User, oldName string) (error, int) {
+	code := 500
+	err := repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		res, err := tx.Execute(ctx, `UPDATE users SET user_name=$1, visibility=$2,
+			version=version+1 WHERE id=$3 AND version=$4`, user.Name, user.Visibility, user.Id,
+			user.Version)
+		if errors.Is(err, ErrUniqueViolation) {
+			code = 409
+			return fmt.Errorf("Username '%s' is taken", user.Name)
+		}
+		err, code = checkUpdated(res, err, "User", user.Id)
 		if err != nil {
-			return false, err
+			return err
+		}
+		code = 500
+		if user.Name == oldName {
+			return nil
 		}
-		if !match {
-			return false, nil
+		// The login follows the new name, or the user could not log in anymore
+		_, err = tx.Execute(ctx, `UPDATE loginInfo SET username=$1 WHERE username=$2`,
+			user.Name, oldName)
+		return err
+	})
+	if err != nil {
+		return err, code
+	}
+	return nil, 200
+}
+
+func (repo DbUserRepo) FindByPlayer(ctx context.Context, playerId int) ([]int, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id FROM users
+		WHERE player_id=$1 AND deleted_at IS NULL ORDER BY id`, playerId)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	var id int
+	defer row.Close()
+	for row.Next() {
+		err = row.Scan(&id)
+		if err != nil {
+			return nil, err
 		}
+		ids = append(ids, id)
 	}
-	return true, nil
+	return ids, nil
+}
+
+func (repo DbUserRepo) Erase(ctx context.Context, user usecases.User, erasure usecases.Erasure) (usecases.Erasure, error) {
+	err := repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		statements := []string{
+			`DELETE FROM game_tags WHERE library_id IN (SELECT id FROM libraries WHERE user_id=$1)`,
+			`DELETE FROM library_shares WHERE library_id IN (SELECT id FROM libraries WHERE user_id=$1)`,
+			`DELETE FROM events WHERE user_id=$1`,
+			`DELETE FROM gamesInLib WHERE library_id IN (SELECT id FROM libraries WHERE user_id=$1)`,
+			`DELETE FROM libraries WHERE user_id=$1`,
+			`DELETE FROM wishlist WHERE user_id=$1`,
+			`DELETE FROM notifications WHERE user_id=$1`,
+			`DELETE FROM friendships WHERE user_id=$1 OR friend_id=$1`,
+			`DELETE FROM follows WHERE follower_id=$1 OR followee_id=$1`,
+			`DELETE FROM users WHERE id=$1`,
+		}
+		for _, statement := range statements {
+			_, err := tx.Execute(ctx, statement, user.Id)
+			if err != nil {
+				return err
+			}
+		}
+		_, err := tx.Execute(ctx, `DELETE FROM loginInfo WHERE username=$1`, user.Name)
+		if err != nil {
+			return err
+		}
+
+		row, err := tx.Query(ctx, `SELECT id FROM users WHERE player_id=$1 LIMIT 1`, user.Player.Id)
+		if err != nil {
+			return err
+		}
+		erasure.PlayerErased = !row.Next()
+		row.Close()
+		if erasure.PlayerErased {
+			_, err = tx.Execute(ctx, `DELETE FROM player_achievements WHERE player_id=$1`, user.Player.Id)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Execute(ctx, `DELETE FROM players WHERE id=$1`, user.Player.Id)
+			if err != nil {
+				return err
+			}
+		}
+
+		erasure.Id, err = tx.QueryRow(ctx, `INSERT INTO erasures (user_id, player_id, user_name_hash,
+			player_erased, erased_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`, erasure.UserId,
+			erasure.PlayerId, erasure.UserNameHash, erasure.PlayerErased, erasure.ErasedAt)
+		return err
+	})
+	return erasure, err
 }
 
-func (repo DbUserRepo) AddLoginInfo(username, password string) error {
-	_, err := repo.dbHandler.Execute(`INSERT INTO loginInfo (username, password)
+func (repo DbUserRepo) AddLoginInfo(ctx context.Context, username, password string) error {
+	_, err := repo.dbHandler.Execute(ctx, `INSERT INTO loginInfo (username, password)
 		VALUES ($1, $2)`, username, password)
 	if err != nil {
 		return err
@@ -150,9 +342,12 @@ func (repo DbUserRepo) AddLoginInfo(username, password string) error {
 	return nil
 }
 
-func (repo DbUserRepo) FindLoginId(username, password string) (int, bool, error) {
-	row, err := repo.dbHandler.Query(`SELECT id FROM loginInfo WHERE username=$1
-		AND password=$2 LIMIT 1`, username, password)
+// FindLoginId gives the id of the user logging in, the id its tokens carry.
+// Logins have their own ids, which do not follow the ones of the users.
+func (repo DbUserRepo) FindLoginId(ctx context.Context, username, password string) (int, bool, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT users.id FROM loginInfo
+		JOIN users ON users.user_name = loginInfo.username
+		WHERE loginInfo.username=$1 AND loginInfo.password=$2 LIMIT 1`, username, password)
 	if err != nil {
 		return 0, false, err
 	}
@@ -170,8 +365,8 @@ func (repo DbUserRepo) FindLoginId(username, password string) (int, bool, error)
 	return id, true, nil
 }
 
-func (repo DbUserRepo) RemoveLoginInfo(user usecases.User) error {
-	_, err := repo.dbHandler.Execute(`DELETE FROM loginInfo WHERE username=$1`, user.Name)
+func (repo DbUserRepo) RemoveLoginInfo(ctx context.Context, user usecases.User) error {
+	_, err := repo.dbHandler.Execute(ctx, `DELETE FROM loginInfo WHERE username=$1`, user.Name)
 	return err
 }
 
@@ -182,21 +377,17 @@ func NewDbPlayerRepo(dbHandlers map[string]DbHandler) *DbPlayerRepo {
 	return dbPlayerRepo
 }
 
-func (repo DbPlayerRepo) Store(player domain.Player) error {
-	existed, err := repo.playerExisted(player.Name)
-	if err != nil {
-		return err
-	}
-	if existed {
-		return nil
-	}
-	_, err = repo.dbHandler.Execute(`INSERT INTO players (player_name)
-		VALUES ($1)`, player.Name)
-	return err
+// Store inserts the player or gets the one already holding its name, and
+// returns its id. The unique name keeps concurrent stores from duplicating it.
+func (repo DbPlayerRepo) Store(ctx context.Context, player domain.Player) (int, error) {
+	id, err := repo.dbHandler.QueryRow(ctx, `INSERT INTO players (player_name) VALUES ($1)
+		ON CONFLICT (player_name) DO UPDATE SET player_name=EXCLUDED.player_name
+		RETURNING id`, player.Name)
+	return id, err
 }
 
-func (repo DbPlayerRepo) FindById(id int) (domain.Player, error, int) {
-	row, err := repo.dbHandler.Query(`SELECT player_name FROM players WHERE id = $1 LIMIT 1`, id)
+func (repo DbPlayerRepo) FindById(ctx context.Context, id int) (domain.Player, error, int) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT player_name FROM players WHERE id = $1 LIMIT 1`, id)
 	if err != nil {
 		return domain.Player{}, err, 500
 	}
@@ -210,18 +401,109 @@ func (repo DbPlayerRepo) FindById(id int) (domain.Player, error, int) {
 	return domain.Player{Id: id, Name: name}, nil, 200
 }
 
-func (repo DbPlayerRepo) playerExisted(playerName string) (bool, error) {
-	row, err := repo.dbHandler.Query(`SELECT player_name FROM players
+func (repo DbPlayerRepo) playerExisted(ctx context.Context, playerName string) (bool, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT player_name FROM players
 		WHERE player_name=$1 LIMIT 1`, playerName)
+	if err != nil {
+		return false, err
+	}
 	defer row.Close()
-	return row.Next(), err
+	return row.Next(), nil
 }
 
-func (repo DbPlayerRepo) nameMatchesId(playerName string, id int) (bool, error) {
-	row, err := repo.dbHandler.Query(`SELECT * FROM players
+func (repo DbPlayerRepo) NameMatchesId(ctx context.Context, playerName string, id int) (bool, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id FROM players
 		WHERE id=$1 AND player_name=$2 LIMIT 1`, id, playerName)
+	if err != nil {
+		return false, err
+	}
 	defer row.Close()
-	return row.Next(), err
+	return row.Next(), nil
+}
+
+func (repo DbPlayerRepo) Rename(ctx context.Context, player domain.Player) (error, int) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id FROM players
+		WHERE player_name=$1 AND id<>$2 LIMIT 1`, player.Name, player.Id)
+	if err != nil {
+		return err, 500
+	}
+	taken := row.Next()
+	row.Close()
+	if taken {
+		return fmt.Errorf("Player name '%s' is taken", player.Name), 409
+	}
+	res, err := repo.dbHandler.Execute(ctx, `UPDATE players SET player_name=$1 WHERE id=$2`,
+		player.Name, player.Id)
+	if err != nil {
+		return err, 500
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err, 500
+	}
+	if updated == 0 {
+		return fmt.Errorf("Player #%d does not exist", player.Id), 404
+	}
+	return nil, 200
+}
+
+// Merge moves the accounts and achievements of the other player to the
+// player, keeping the earliest unlock of the achievements both had, and
+// removes the other player.
+func (repo DbPlayerRepo) Merge(ctx context.Context, playerId, otherPlayerId int) error {
+	return repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		_, err := tx.Execute(ctx, `UPDATE users SET player_id=$1 WHERE player_id=$2`,
+			playerId, otherPlayerId)
+		if err != nil {
+			return err
+		}
+		return mergePlayer(ctx, tx, playerId, otherPlayerId)
+	})
+}
+
+// Link moves the user under the player. The player the user leaves is merged
+// into it when the user was its last account.
+func (repo DbPlayerRepo) Link(ctx context.Context, playerId int, user usecases.User) error {
+	return repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		_, err := tx.Execute(ctx, `UPDATE users SET player_id=$1 WHERE id=$2`, playerId, user.Id)
+		if err != nil {
+			return err
+		}
+		row, err := tx.Query(ctx, `SELECT id FROM users WHERE player_id=$1 LIMIT 1`, user.Player.Id)
+		if err != nil {
+			return err
+		}
+		left := row.Next()
+		row.Close()
+		if left {
+			return nil
+		}
+		return mergePlayer(ctx, tx, playerId, user.Player.Id)
+	})
+}
+
+// mergePlayer moves the achievements of a player without accounts left to
+// another one, keeping the earliest unlock of those both had, and removes it.
+func mergePlayer(ctx context.Context, tx DbHandler, playerId, otherPlayerId int) error {
+	statements := []string{
+		`UPDATE player_achievements AS mine SET unlocked_at=theirs.unlocked_at
+			FROM player_achievements AS theirs
+			WHERE mine.player_id=$1 AND theirs.player_id=$2
+			AND mine.achievement_id=theirs.achievement_id
+			AND theirs.unlocked_at < mine.unlocked_at`,
+		`UPDATE player_achievements SET player_id=$1
+			WHERE player_id=$2 AND achievement_id NOT IN (
+				SELECT achievement_id FROM player_achievements WHERE player_id=$1)`,
+		`DELETE FROM player_achievements WHERE player_id=$2`,
+		`DELETE FROM players WHERE id=$2`,
+	}
+	for _, statement := range statements {
+		_, err := tx.Execute(ctx, statement, playerId, otherPlayerId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func NewDbLibraryRepo(dbHandlers map[string]DbHandler) *DbLibraryRepo {
@@ -231,39 +513,94 @@ func NewDbLibraryRepo(dbHandlers map[string]DbHandler) *DbLibraryRepo {
 	return dbLibraryRepo
 }
 
-func (repo DbLibraryRepo) Store(library usecases.Library) (int, error) {
-	id, err := repo.dbHandler.QueryRow(`INSERT INTO libraries (user_id) VALUES ($1) RETURNING id`,
-		library.User.Id)
+func (repo DbLibraryRepo) Store(ctx context.Context, library usecases.Library) (int, error) {
+	id, err := repo.dbHandler.QueryRow(ctx, `INSERT INTO libraries (user_id, name, description,
+		visibility) VALUES ($1, $2, $3, $4) RETURNING id`, library.User.Id, library.Name,
+		library.Description, library.Visibility)
 	return id, err
 }
 
-func (repo DbLibraryRepo) Remove(library usecases.Library) error {
-	_, err := repo.dbHandler.Execute(`DELETE FROM libraries WHERE id=$1`, library.Id)
+func (repo DbLibraryRepo) Remove(ctx context.Context, library usecases.Library, deletedAt time.Time) error {
+	_, err := repo.dbHandler.Execute(ctx, `UPDATE libraries SET deleted_at=$2 WHERE id=$1`,
+		library.Id, deletedAt)
 	return err
 }
 
-func (repo DbLibraryRepo) FindById(id int) (usecases.Library, error, int) {
-	row, err := repo.dbHandler.Query(`SELECT user_id FROM libraries WHERE id = $1 LIMIT 1`, id)
+func (repo DbLibraryRepo) FindDeleted(ctx context.Context, id int) (usecases.Library, time.Time, error, int) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT user_id, name, deleted_at FROM libraries
+		WHERE id=$1 AND deleted_at IS NOT NULL LIMIT 1`, id)
+	if err != nil {
+		return usecases.Library{}, time.Time{}, err, 500
+	}
+	library := usecases.Library{Id: id}
+	var deletedAt time.Time
+	defer row.Close()
+	if !row.Next() {
+		return usecases.Library{}, time.Time{}, fmt.Errorf("Library #%d is not deleted", id), 404
+	}
+	err = row.Scan(&library.User.Id, &library.Name, &deletedAt)
+	if err != nil {
+		return usecases.Library{}, time.Time{}, err, 500
+	}
+	return library, deletedAt, nil, 200
+}
+
+func (repo DbLibraryRepo) Restore(ctx context.Context, library usecases.Library) error {
+	_, err := repo.dbHandler.Execute(ctx, `UPDATE libraries SET deleted_at=NULL WHERE id=$1`,
+		library.Id)
+	return err
+}
+
+// Purge removes for good the libraries deleted before the given time, with
+// the games listed in them. The games themselves stay in the catalog.
+func (repo DbLibraryRepo) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	var purged int64
+	err := repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		_, err := tx.Execute(ctx, `DELETE FROM gamesInLib WHERE library_id IN (
+			SELECT id FROM libraries WHERE deleted_at < $1)`, deletedBefore)
+		if err != nil {
+			return err
+		}
+		res, err := tx.Execute(ctx, `DELETE FROM libraries WHERE deleted_at < $1`, deletedBefore)
+		if err != nil {
+			return err
+		}
+		purged, err = res.RowsAffected()
+		return err
+	})
+	return int(purged), err
+}
+
+func (repo DbLibraryRepo) FindById(ctx context.Context, id int) (usecases.Library, error, int) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT user_id, name, description, visibility, version
+		FROM libraries WHERE id = $1 AND deleted_at IS NULL LIMIT 1`, id)
 	if err != nil {
 		return usecases.Library{}, err, 500
 	}
 
-	var userId int
+	var (
+		userId      int
+		name        string
+		description string
+		visibility  string
+		version     int
+	)
 	defer row.Close()
 	row.Next()
-	err = row.Scan(&userId)
+	err = row.Scan(&userId, &name, &description, &visibility, &version)
 	if err != nil {
 		return usecases.Library{}, err, 404
 	}
 	userRepo := NewDbUserRepo(repo.dbHandlers)
-	user, err, code := userRepo.FindById(userId)
+	user, err, code := userRepo.FindById(ctx, userId)
 	if err != nil {
 		return usecases.Library{}, err, code
 	}
-	library := usecases.Library{Id: id, User: user}
+	library := usecases.Library{Id: id, User: user, Name: name, Description: description,
+		Visibility: visibility, Version: version}
 
 	var gameId int
-	row, err = repo.dbHandler.Query(`SELECT id FROM gamesInLib WHERE library_id = $1`, library.Id)
+	row, err = repo.dbHandler.Query(ctx, `SELECT game_id FROM gamesInLib WHERE library_id = $1`, library.Id)
 	if err != nil {
 		return library, err, 500
 	}
@@ -278,6 +615,13 @@ func (repo DbLibraryRepo) FindById(id int) (usecases.Library, error, int) {
 	return library, err, 200
 }
 
+func (repo DbLibraryRepo) Update(ctx context.Context, library usecases.Library) (error, int) {
+	res, err := repo.dbHandler.Execute(ctx, `UPDATE libraries SET name=$1, description=$2,
+		visibility=$3, version=version+1 WHERE id=$4 AND version=$5`, library.Name,
+		library.Description, library.Visibility, library.Id, library.Version)
+	return checkUpdated(res, err, "Library", library.Id)
+}
+
 func NewDbGameRepo(dbHandlers map[string]DbHandler) *DbGameRepo {
 	dbGameRepo := new(DbGameRepo)
 	dbGameRepo.dbHandlers = dbHandlers
@@ -285,18 +629,21 @@ func NewDbGameRepo(dbHandlers map[string]DbHandler) *DbGameRepo {
 	return dbGameRepo
 }
 
-func (repo DbGameRepo) Store(game usecases.Game) (int, error) {
-	id, existed, err := repo.gameExisted(game.Name)
+func (repo DbGameRepo) Store(ctx context.Context, game usecases.Game) (int, bool, error) {
+	id, existed, err := repo.gameExisted(ctx, game.Name)
+	if err != nil {
+		return 0, false, err
+	}
 	if !existed {
-		id, err = repo.dbHandler.QueryRow(`INSERT INTO games (name, producer, value)
+		id, err = repo.dbHandler.QueryRow(ctx, `INSERT INTO games (name, producer, value)
     	VALUES ($1, $2, $3) RETURNING id`, game.Name, game.Producer, game.Value)
-		return id, err
+		return id, err == nil, err
 	}
-	return id, nil
+	return id, false, nil
 }
 
-func (repo DbGameRepo) AddToLib(gameId, libraryId int) (error, int) {
-	existed, err := repo.gameExistedInLib(gameId, libraryId)
+func (repo DbGameRepo) AddToLib(ctx context.Context, gameId, libraryId int) (error, int) {
+	existed, err := repo.gameExistedInLib(ctx, gameId, libraryId)
 	if err != nil {
 		return err, 500
 	}
@@ -304,7 +651,7 @@ func (repo DbGameRepo) AddToLib(gameId, libraryId int) (error, int) {
 		err = fmt.Errorf("Game already existed in library")
 		return err, 400
 	}
-	_, err = repo.dbHandler.Execute(`INSERT INTO gamesInLib (game_id, library_id)
+	_, err = repo.dbHandler.Execute(ctx, `INSERT INTO gamesInLib (game_id, library_id)
 		VALUES ($1, $2)`, gameId, libraryId)
 	if err != nil {
 		return err, 500
@@ -312,25 +659,196 @@ func (repo DbGameRepo) AddToLib(gameId, libraryId int) (error, int) {
 	return nil, 200
 }
 
-func (repo DbGameRepo) RemoveFromLib(game usecases.Game, libraryId int) error {
-	_, err := repo.dbHandler.Execute(`DELETE FROM gamesInLib WHERE game_id=$1 AND library_id=$2`,
-		game.Id, libraryId)
-	return err
+func (repo DbGameRepo) RemoveFromLib(ctx context.Context, game usecases.Game, libraryId int) error {
+	return repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		_, err := tx.Execute(ctx, `DELETE FROM game_tags WHERE game_id=$1 AND library_id=$2`,
+			game.Id, libraryId)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Execute(ctx, `DELETE FROM gamesInLib WHERE game_id=$1 AND library_id=$2`,
+			game.Id, libraryId)
+		return err
+	})
+}
+
+func (repo DbGameRepo) Transfer(ctx context.Context, gameIds []int, fromLibraryId, toLibraryId int, keep bool) ([]usecases.Transfer, error) {
+	var transfers []usecases.Transfer
+	err := repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		transfers = nil
+		for _, gameId := range gameIds {
+			status, err := transferGame(ctx, tx, gameId, fromLibraryId, toLibraryId, keep)
+			if err != nil {
+				return err
+			}
+			transfers = append(transfers, usecases.Transfer{GameId: gameId, Status: status})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func transferGame(ctx context.Context, tx DbHandler, gameId, fromLibraryId, toLibraryId int, keep bool) (string, error) {
+	row, err := tx.Query(ctx, `SELECT id FROM gamesInLib WHERE game_id=$1 AND library_id=$2
+		LIMIT 1 FOR UPDATE`, gameId, fromLibraryId)
+	if err != nil {
+		return "", err
+	}
+	var entryId int
+	existed := row.Next()
+	if existed {
+		err = row.Scan(&entryId)
+	}
+	row.Close()
+	if err != nil {
+		return "", err
+	}
+	if !existed {
+		return usecases.TransferMissing, nil
+	}
+
+	row, err = tx.Query(ctx, `SELECT id FROM gamesInLib WHERE game_id=$1 AND library_id=$2
+		LIMIT 1`, gameId, toLibraryId)
+	if err != nil {
+		return "", err
+	}
+	conflict := row.Next()
+	row.Close()
+	if conflict {
+		return usecases.TransferConflict, nil
+	}
+
+	if keep {
+		_, err = tx.Execute(ctx, `INSERT INTO gamesInLib (game_id, library_id) VALUES ($1, $2)`,
+			gameId, toLibraryId)
+		if err != nil {
+			return "", err
+		}
+		_, err = tx.Execute(ctx, `INSERT INTO game_tags (library_id, game_id, tag)
+			SELECT $3, game_id, tag FROM game_tags WHERE library_id=$1 AND game_id=$2`,
+			fromLibraryId, gameId, toLibraryId)
+		return usecases.TransferCopied, err
+	}
+	_, err = tx.Execute(ctx, `UPDATE gamesInLib SET library_id=$1 WHERE id=$2`, toLibraryId, entryId)
+	if err != nil {
+		return "", err
+	}
+	_, err = tx.Execute(ctx, `UPDATE game_tags SET library_id=$1 WHERE library_id=$2 AND game_id=$3`,
+		toLibraryId, fromLibraryId, gameId)
+	return usecases.TransferMoved, err
+}
+
+// EachInLibraries streams the games of one library of the user, or of all of
+// them when libraryId is 0, with their tags.
+func (repo DbGameRepo) EachInLibraries(ctx context.Context, userId, libraryId int, fn func(entry usecases.LibraryEntry) error) error {
+	row, err := repo.dbHandler.Query(ctx, `SELECT gamesInLib.library_id, games.id, games.name,
+		games.producer, games.value, (SELECT string_agg(game_tags.tag, chr(31) ORDER BY game_tags.tag)
+			FROM game_tags WHERE game_tags.library_id = gamesInLib.library_id
+			AND game_tags.game_id = games.id)
+		FROM gamesInLib
+		JOIN libraries ON libraries.id = gamesInLib.library_id
+		JOIN games ON games.id = gamesInLib.game_id
+		WHERE libraries.user_id=$1 AND libraries.deleted_at IS NULL
+		AND ($2 = 0 OR libraries.id = $2)
+		ORDER BY gamesInLib.library_id, games.id`, userId, libraryId)
+	if err != nil {
+		return err
+	}
+	defer row.Close()
+	for row.Next() {
+		var entry usecases.LibraryEntry
+		var tags sql.NullString
+		err = row.Scan(&entry.LibraryId, &entry.Game.Id, &entry.Game.Name,
+			&entry.Game.Producer, &entry.Game.Value, &tags)
+		if err != nil {
+			return err
+		}
+		if tags.Valid {
+			entry.Game.Tags = strings.Split(tags.String, "\x1f")
+		}
+		err = fn(entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (repo DbGameRepo) gameExisted(name string) (int, bool, error) {
-	row, err := repo.dbHandler.Query(`SELECT id FROM games
+var errDryRun = errors.New("dry run")
+
+func (repo DbGameRepo) Import(ctx context.Context, libraryId int, rows []usecases.ImportRow, dryRun bool) ([]usecases.ImportResult, error) {
+	var results []usecases.ImportResult
+	err := repo.dbHandler.Transaction(ctx, func(tx DbHandler) error {
+		results = nil
+		txRepo := DbGameRepo{dbHandlers: repo.dbHandlers, dbHandler: tx}
+		for _, row := range rows {
+			result, err := txRepo.importRow(ctx, libraryId, row)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && err != errDryRun {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (repo DbGameRepo) importRow(ctx context.Context, libraryId int, row usecases.ImportRow) (usecases.ImportResult, error) {
+	result := usecases.ImportResult{Line: row.Line, Name: row.Name}
+	game := usecases.Game{Name: row.Name, Producer: row.Producer, Value: row.Value}
+	id, created, err := repo.Store(ctx, game)
+	if err != nil {
+		return result, err
+	}
+	result.GameId = id
+	if created {
+		price := usecases.GamePrice{GameId: id, Value: row.Value,
+			Source: usecases.PriceSourceCatalog, RecordedAt: time.Now().UTC()}
+		err = repo.RecordPrice(ctx, price)
+		if err != nil {
+			return result, err
+		}
+		result.Status = usecases.ImportCreated
+	} else {
+		// Like AddGame, a game the catalog knows keeps its value
+		existed, err := repo.gameExistedInLib(ctx, id, libraryId)
+		if err != nil {
+			return result, err
+		}
+		if existed {
+			result.Status = usecases.ImportDuplicate
+			result.Reason = "Game already existed in library"
+			return result, nil
+		}
+		result.Status = usecases.ImportLinked
+	}
+	_, err = repo.dbHandler.Execute(ctx, `INSERT INTO gamesInLib (game_id, library_id)
+		VALUES ($1, $2)`, id, libraryId)
+	return result, err
+}
+
+func (repo DbGameRepo) gameExisted(ctx context.Context, name string) (int, bool, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id FROM games
 		WHERE name=$1 LIMIT 1`, name)
 	if err != nil {
 		return 0, false, err
 	}
 
+	defer row.Close()
 	exist := row.Next()
 	if !exist {
 		return 0, false, nil
 	}
 	var id int
-	defer row.Close()
 	err = row.Scan(&id)
 	if err != nil {
 		return 0, false, err
@@ -338,8 +856,8 @@ func (repo DbGameRepo) gameExisted(name string) (int, bool, error) {
 	return id, true, nil
 }
 
-func (repo DbGameRepo) gameExistedInLib(gameId, libraryId int) (bool, error) {
-	row, err := repo.dbHandler.Query(`SELECT id FROM gamesInLib
+func (repo DbGameRepo) gameExistedInLib(ctx context.Context, gameId, libraryId int) (bool, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id FROM gamesInLib
 		WHERE game_id=$1 AND library_id=$2 LIMIT 1`, gameId, libraryId)
 	if err != nil {
 		return false, err
@@ -348,8 +866,77 @@ func (repo DbGameRepo) gameExistedInLib(gameId, libraryId int) (bool, error) {
 	return row.Next(), nil
 }
 
-func (repo DbGameRepo) FindById(id int) (usecases.Game, error, int) {
-	row, err := repo.dbHandler.Query(`SELECT name, producer, value FROM games
+func (repo DbGameRepo) UpdateValue(ctx context.Context, game usecases.Game) error {
+	_, err := repo.dbHandler.Execute(ctx, `UPDATE games SET value=$1, version=version+1 WHERE id=$2`,
+		game.Value, game.Id)
+	return err
+}
+
+func (repo DbGameRepo) Update(ctx context.Context, game usecases.Game) (error, int) {
+	res, err := repo.dbHandler.Execute(ctx, `UPDATE games SET name=$1, producer=$2, value=$3,
+		version=version+1 WHERE id=$4 AND version=$5`, game.Name, game.Producer, game.Value,
+		game.Id, game.Version)
+	return checkUpdated(res, err, "Game", game.Id)
+}
+
+func (repo DbGameRepo) FindIdByName(ctx context.Context, name string) (int, bool, error) {
+	return repo.gameExisted(ctx, name)
+}
+
+func (repo DbGameRepo) RecordPrice(ctx context.Context, price usecases.GamePrice) error {
+	_, err := repo.dbHandler.Execute(ctx, `INSERT INTO game_prices (game_id, value, source, recorded_at)
+		VALUES ($1, $2, $3, $4)`, price.GameId, price.Value, price.Source, price.RecordedAt)
+	return err
+}
+
+func (repo DbGameRepo) FindPrices(ctx context.Context, gameId int, from, to time.Time) ([]usecases.GamePrice, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT value, source, recorded_at FROM game_prices
+		WHERE game_id=$1 AND recorded_at >= $2 AND recorded_at <= $3
+		ORDER BY recorded_at`, gameId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var prices []usecases.GamePrice
+	defer row.Close()
+	for row.Next() {
+		price := usecases.GamePrice{GameId: gameId}
+		err = row.Scan(&price.Value, &price.Source, &price.RecordedAt)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	return prices, nil
+}
+
+func (repo DbGameRepo) LowestPrice(ctx context.Context, gameId int) (float64, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT LEAST(MIN(game_prices.value), games.value)
+		FROM games LEFT JOIN game_prices ON game_prices.game_id = games.id
+		WHERE games.id=$1 GROUP BY games.value`, gameId)
+	if err != nil {
+		return 0, err
+	}
+	var lowest float64
+	defer row.Close()
+	row.Next()
+	err = row.Scan(&lowest)
+	return lowest, err
+}
+
+func (repo DbGameRepo) InUserLibraries(ctx context.Context, gameId, userId int) (bool, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT gamesInLib.id FROM gamesInLib
+		JOIN libraries ON libraries.id = gamesInLib.library_id
+		WHERE gamesInLib.game_id=$1 AND libraries.user_id=$2
+		AND libraries.deleted_at IS NULL LIMIT 1`, gameId, userId)
+	if err != nil {
+		return false, err
+	}
+	defer row.Close()
+	return row.Next(), nil
+}
+
+func (repo DbGameRepo) FindById(ctx context.Context, id int) (usecases.Game, error, int) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT name, producer, value, version FROM games
     	WHERE id = $1 LIMIT 1`, id)
 	if err != nil {
 		return usecases.Game{}, err, 500
@@ -358,20 +945,33 @@ func (repo DbGameRepo) FindById(id int) (usecases.Game, error, int) {
 		name     string
 		producer string
 		value    float64
+		version  int
 	)
 
 	defer row.Close()
 	row.Next()
-	err = row.Scan(&name, &producer, &value)
+	err = row.Scan(&name, &producer, &value, &version)
 	if err != nil {
 		return usecases.Game{}, err, 404
 	}
 
-	game := usecases.Game{Id: id, Name: name, Producer: producer, Value: value}
+	game := usecases.Game{Id: id, Name: name, Producer: producer, Value: value, Version: version}
 	return game, nil, 200
 }
 
-func (repo LoggerRepo) Log(message string) error {
-	fmt.Println(message)
-	return nil
+// checkUpdated tells a version guarded update that matched no row apart
+// from a failed one.
+func checkUpdated(res sql.Result, err error, resource string, id int) (error, int) {
+	if err != nil {
+		return err, 500
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err, 500
+	}
+	if updated == 0 {
+		err = fmt.Errorf("%s #%d was modified by another request", resource, id)
+		return err, 412
+	}
+	return nil, 200
 }
@@ -0,0 +1,94 @@
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"game-tracker/usecases"
+)
+
+type DbShareRepo DbRepo
+
+func NewDbShareRepo(dbHandlers map[string]DbHandler) *DbShareRepo {
+	dbShareRepo := new(DbShareRepo)
+	dbShareRepo.dbHandlers = dbHandlers
+	dbShareRepo.dbHandler = dbHandlers["DbShareRepo"]
+	return dbShareRepo
+}
+
+func (repo DbShareRepo) Store(ctx context.Context, share usecases.Share) (int, error) {
+	id, err := repo.dbHandler.QueryRow(ctx, `INSERT INTO library_shares (library_id, token,
+		created_at) VALUES ($1, $2, $3) RETURNING id`, share.LibraryId, share.Token,
+		share.CreatedAt)
+	return id, err
+}
+
+func (repo DbShareRepo) FindByLibrary(ctx context.Context, libraryId int) ([]usecases.Share, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id, token, created_at, revoked_at
+		FROM library_shares WHERE library_id=$1 ORDER BY created_at`, libraryId)
+	if err != nil {
+		return nil, err
+	}
+	var shares []usecases.Share
+	defer row.Close()
+	for row.Next() {
+		share, err := scanShare(row, usecases.Share{LibraryId: libraryId})
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+func (repo DbShareRepo) FindByToken(ctx context.Context, token string) (usecases.Share, error, int) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id, library_id, created_at, revoked_at
+		FROM library_shares WHERE token=$1 LIMIT 1`, token)
+	if err != nil {
+		return usecases.Share{}, err, 500
+	}
+	defer row.Close()
+	if !row.Next() {
+		return usecases.Share{}, fmt.Errorf("Share link does not exist"), 404
+	}
+	share := usecases.Share{Token: token}
+	var revokedAt sql.NullTime
+	err = row.Scan(&share.Id, &share.LibraryId, &share.CreatedAt, &revokedAt)
+	if err != nil {
+		return usecases.Share{}, err, 500
+	}
+	if revokedAt.Valid {
+		share.RevokedAt = &revokedAt.Time
+	}
+	return share, nil, 200
+}
+
+func (repo DbShareRepo) Revoke(ctx context.Context, libraryId, shareId int, revokedAt time.Time) (error, int) {
+	res, err := repo.dbHandler.Execute(ctx, `UPDATE library_shares SET revoked_at=$3
+		WHERE id=$1 AND library_id=$2 AND revoked_at IS NULL`, shareId, libraryId, revokedAt)
+	if err != nil {
+		return err, 500
+	}
+	revoked, err := res.RowsAffected()
+	if err != nil {
+		return err, 500
+	}
+	if revoked == 0 {
+		return fmt.Errorf("Share #%d of library #%d does not exist", shareId, libraryId), 404
+	}
+	return nil, 200
+}
+
+func scanShare(row Row, share usecases.Share) (usecases.Share, error) {
+	var revokedAt sql.NullTime
+	err := row.Scan(&share.Id, &share.Token, &share.CreatedAt, &revokedAt)
+	if err != nil {
+		return usecases.Share{}, err
+	}
+	if revokedAt.Valid {
+		share.RevokedAt = &revokedAt.Time
+	}
+	return share, nil
+}
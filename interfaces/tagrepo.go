@@ -0,0 +1,127 @@
+package interfaces
+
+import (
+	"context"
+	"strings"
+
+	"game-tracker/usecases"
+)
+
+const maxTagSuggestions = 20
+
+type DbTagRepo DbRepo
+
+func NewDbTagRepo(dbHandlers map[string]DbHandler) *DbTagRepo {
+	dbTagRepo := new(DbTagRepo)
+	dbTagRepo.dbHandlers = dbHandlers
+	dbTagRepo.dbHandler = dbHandlers["DbTagRepo"]
+	return dbTagRepo
+}
+
+func (repo DbTagRepo) Store(ctx context.Context, libraryId, gameId int, tag string) error {
+	_, err := repo.dbHandler.Execute(ctx, `INSERT INTO game_tags (library_id, game_id, tag)
+		VALUES ($1, $2, $3)`, libraryId, gameId, tag)
+	return err
+}
+
+func (repo DbTagRepo) Remove(ctx context.Context, libraryId, gameId int, tag string) error {
+	_, err := repo.dbHandler.Execute(ctx, `DELETE FROM game_tags
+		WHERE library_id=$1 AND game_id=$2 AND tag=$3`, libraryId, gameId, tag)
+	return err
+}
+
+func (repo DbTagRepo) FindTags(ctx context.Context, libraryId, gameId int) ([]string, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT tag FROM game_tags
+		WHERE library_id=$1 AND game_id=$2 ORDER BY tag`, libraryId, gameId)
+	if err != nil {
+		return nil, err
+	}
+	return scanTags(row)
+}
+
+func (repo DbTagRepo) FindUserTags(ctx context.Context, userId int, prefix string) ([]string, error) {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	row, err := repo.dbHandler.Query(ctx, `SELECT DISTINCT game_tags.tag FROM game_tags
+		JOIN libraries ON libraries.id = game_tags.library_id
+		WHERE libraries.user_id=$1 AND libraries.deleted_at IS NULL AND game_tags.tag LIKE $2
+		ORDER BY game_tags.tag LIMIT $3`, userId, escaper.Replace(prefix)+"%", maxTagSuggestions)
+	if err != nil {
+		return nil, err
+	}
+	return scanTags(row)
+}
+
+func (repo DbTagRepo) FindUserGames(ctx context.Context, userId int, tag string) ([]usecases.LibraryEntry, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT gamesInLib.library_id, games.id, games.name,
+		games.producer, games.value FROM gamesInLib
+		JOIN libraries ON libraries.id = gamesInLib.library_id
+		JOIN games ON games.id = gamesInLib.game_id
+		WHERE libraries.user_id=$1 AND libraries.deleted_at IS NULL AND ($2 = '' OR EXISTS (
+			SELECT 1 FROM game_tags WHERE game_tags.library_id = gamesInLib.library_id
+			AND game_tags.game_id = gamesInLib.game_id AND game_tags.tag = $2))
+		ORDER BY gamesInLib.library_id, games.id`, userId, tag)
+	if err != nil {
+		return nil, err
+	}
+	var entries []usecases.LibraryEntry
+	defer row.Close()
+	for row.Next() {
+		var entry usecases.LibraryEntry
+		err = row.Scan(&entry.LibraryId, &entry.Game.Id, &entry.Game.Name,
+			&entry.Game.Producer, &entry.Game.Value)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	tags, err := repo.userTags(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range entries {
+		entries[i].Game.Tags = tags[[2]int{entry.LibraryId, entry.Game.Id}]
+	}
+	return entries, nil
+}
+
+// userTags loads every tag of the user at once, keyed by library and game.
+func (repo DbTagRepo) userTags(ctx context.Context, userId int) (map[[2]int][]string, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT game_tags.library_id, game_tags.game_id,
+		game_tags.tag FROM game_tags
+		JOIN libraries ON libraries.id = game_tags.library_id
+		WHERE libraries.user_id=$1 AND libraries.deleted_at IS NULL ORDER BY game_tags.tag`, userId)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[[2]int][]string)
+	var (
+		libraryId int
+		gameId    int
+		tag       string
+	)
+	defer row.Close()
+	for row.Next() {
+		err = row.Scan(&libraryId, &gameId, &tag)
+		if err != nil {
+			return nil, err
+		}
+		key := [2]int{libraryId, gameId}
+		tags[key] = append(tags[key], tag)
+	}
+	return tags, nil
+}
+
+func scanTags(row Row) ([]string, error) {
+	var tags []string
+	var tag string
+	defer row.Close()
+	for row.Next() {
+		err := row.Scan(&tag)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,82 @@
+package interfaces
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+
+	"game-tracker/models/request"
+	"game-tracker/models/result"
+)
+
+func (handler WebserviceHandler) ShowAccounts(c *gin.Context) (int, result.Player) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Player{}
+	}
+
+	user, err, code := handler.interactor(c).ShowUser(c.Request.Context(), userId, userId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Player{}
+	}
+	player, users, err, code := handler.interactor(c).ShowPlayer(c.Request.Context(), user.Id, user.Player.Id)
+	if err != nil {
+		c.Error(err)
+		return code, result.Player{}
+	}
+	return 200, playerMessage(player, users)
+}
+
+func (handler WebserviceHandler) LinkAccount(c *gin.Context) (int, result.Player) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Player{}
+	}
+	loginInfo := request.LoginInfo{}
+	err = c.BindJSON(&loginInfo)
+	if err != nil {
+		return 400, result.Player{}
+	}
+
+	player, users, err, code := handler.interactor(c).LinkAccount(c.Request.Context(), userId, loginInfo.Username,
+		loginInfo.Password)
+	if err != nil {
+		c.Error(err)
+		return code, result.Player{}
+	}
+	return 201, playerMessage(player, users)
+}
+
+func (handler WebserviceHandler) ShowAccountGames(c *gin.Context) (int, result.AccountGames) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.AccountGames{}
+	}
+	duplicatesOnly := false
+	if value := c.Query("duplicates"); value != "" {
+		duplicatesOnly, err = strconv.ParseBool(value)
+		if err != nil {
+			c.Error(fmt.Errorf("duplicates must be true or false"))
+			return 400, result.AccountGames{}
+		}
+	}
+
+	games, err, code := handler.interactor(c).ShowAccountGames(c.Request.Context(), userId, duplicatesOnly)
+	if err != nil {
+		c.Error(err)
+		return code, result.AccountGames{}
+	}
+
+	message := result.AccountGames{UserId: userId, DuplicatesOnly: duplicatesOnly}
+	for _, game := range games {
+		message.Games = append(message.Games, result.AccountGame{GameId: game.Game.Id,
+			Name: game.Game.Name, Producer: game.Game.Producer, Value: game.Game.Value,
+			UserIds: game.UserIds, LibraryIds: game.LibraryIds})
+	}
+	handler.logger(c).Debug("Games across accounts shown")
+	return 200, message
+}
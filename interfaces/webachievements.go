@@ -0,0 +1,119 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+
+	"game-tracker/models/request"
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+func (handler WebserviceHandler) AddAchievement(c *gin.Context) (int, result.Achievement) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Achievement{}
+	}
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Achievement{}
+	}
+	achievement := request.Achievement{}
+	err = c.BindJSON(&achievement)
+	if err != nil {
+		return 400, result.Achievement{}
+	}
+
+	id, err, code := handler.interactor(c).AddAchievement(c.Request.Context(), userId, gameId, achievement.Name,
+		achievement.Description, achievement.Points, achievement.Hidden)
+	if err != nil {
+		c.Error(err)
+		return code, result.Achievement{}
+	}
+
+	message := result.Achievement{Id: id, GameId: gameId, UserId: userId, Name: achievement.Name,
+		Description: achievement.Description, Points: achievement.Points, Hidden: achievement.Hidden}
+	handler.logger(c).Debug("Achievement added", "achievementId", id)
+	return 201, message
+}
+
+func (handler WebserviceHandler) UnlockAchievement(c *gin.Context) (int, result.Achievement) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Achievement{}
+	}
+	achievementId, err := strconv.Atoi(c.Param("achId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Achievement{}
+	}
+
+	achievement, err, code := handler.interactor(c).UnlockAchievement(c.Request.Context(), userId, achievementId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Achievement{}
+	}
+
+	message := viewPlayerAchievement(userId, achievement)
+	handler.logger(c).Debug("Achievement unlocked", "achievementId", achievementId)
+	return 201, message
+}
+
+func (handler WebserviceHandler) ShowAchievements(c *gin.Context) (int, result.Achievements) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Achievements{}
+	}
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Achievements{}
+	}
+
+	achievements, completion, err, code := handler.interactor(c).ShowAchievements(c.Request.Context(), userId, gameId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Achievements{}
+	}
+
+	message := result.Achievements{GameId: gameId, UserId: userId, Completion: completion}
+	for _, achievement := range achievements {
+		message.Achievements = append(message.Achievements,
+			viewPlayerAchievement(userId, achievement))
+	}
+	handler.logger(c).Debug("Achievements shown", "gameId", gameId)
+	return 200, message
+}
+
+func (handler WebserviceHandler) ShowGamerScore(c *gin.Context) (int, result.GamerScore) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.GamerScore{}
+	}
+
+	playerId, score, err, code := handler.interactor(c).ShowGamerScore(c.Request.Context(), c.GetInt("userId"), userId)
+	if err != nil {
+		c.Error(err)
+		return code, result.GamerScore{}
+	}
+
+	message := result.GamerScore{UserId: userId, PlayerId: playerId, Score: score}
+	handler.logger(c).Debug("Gamer score shown", "playerId", playerId)
+	return 200, message
+}
+
+func viewPlayerAchievement(userId int, achievement usecases.PlayerAchievement) result.Achievement {
+	message := result.Achievement{Id: achievement.Id, GameId: achievement.GameId, UserId: userId,
+		Name: achievement.Name, Description: achievement.Description, Points: achievement.Points,
+		Hidden: achievement.Hidden, Unlocked: achievement.Unlocked}
+	if achievement.Unlocked {
+		message.UnlockedAt = achievement.UnlockedAt.Format(time.RFC3339)
+	}
+	return message
+}
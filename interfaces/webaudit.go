@@ -0,0 +1,57 @@
+package interfaces
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+func (handler WebserviceHandler) ShowAuditLog(c *gin.Context) (int, result.AuditLog) {
+	filter := usecases.AuditFilter{Action: c.Query("action")}
+	var err error
+	if value := c.Query("actor"); value != "" {
+		filter.ActorId, err = strconv.Atoi(value)
+		if err != nil {
+			c.Error(fmt.Errorf("actor must be a user id"))
+			return 400, result.AuditLog{}
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		filter.Limit, err = strconv.Atoi(value)
+		if err != nil {
+			c.Error(fmt.Errorf("limit must be a number"))
+			return 400, result.AuditLog{}
+		}
+	}
+	filter.From, err = parseDate(c.Query("from"), false)
+	if err != nil {
+		c.Error(err)
+		return 400, result.AuditLog{}
+	}
+	filter.To, err = parseDate(c.Query("to"), true)
+	if err != nil {
+		c.Error(err)
+		return 400, result.AuditLog{}
+	}
+
+	entries, err, code := handler.interactor(c).ShowAuditLog(c.Request.Context(), c.GetInt("userId"), filter)
+	if err != nil {
+		c.Error(err)
+		return code, result.AuditLog{}
+	}
+	message := result.AuditLog{Entries: []result.AuditEntry{}}
+	for _, entry := range entries {
+		message.Entries = append(message.Entries, auditEntryMessage(entry))
+	}
+	return 200, message
+}
+
+func auditEntryMessage(entry usecases.AuditEntry) result.AuditEntry {
+	return result.AuditEntry{Id: entry.Id, Action: entry.Action, ActorId: entry.ActorId,
+		TargetType: entry.TargetType, TargetId: entry.TargetId, Detail: entry.Detail,
+		Ip: entry.Ip, CreatedAt: entry.CreatedAt.Format(time.RFC3339)}
+}
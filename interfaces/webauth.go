@@ -3,6 +3,7 @@ package interfaces
 import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"time"
 
 	"game-tracker/models/request"
 )
@@ -14,13 +15,13 @@ func (handler WebserviceHandler) Login(c *gin.Context) (string, int) {
 		return "", 400
 	}
 
-	id, err, code := handler.ProfileInteractor.FindLoginId(loginInfo.Username, loginInfo.Password)
+	id, err, code := handler.interactor(c).Login(c.Request.Context(), loginInfo.Username, loginInfo.Password)
 	if err != nil {
 		c.Error(err)
 		return "", code
 	}
 
-	tokenString, err := createToken(id)
+	tokenString, err := createToken(id, handler.TokenKey, handler.TokenLifetime)
 	if err != nil {
 		c.Error(err)
 		return "", 500
@@ -29,10 +30,11 @@ func (handler WebserviceHandler) Login(c *gin.Context) (string, int) {
 	return tokenString, 200
 }
 
-func createToken(id int) (string, error) {
+func createToken(id int, key string, lifetime time.Duration) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id": id,
+		"id":  id,
+		"exp": time.Now().Add(lifetime).Unix(),
 	})
-	tokenString, err := token.SignedString([]byte("5230"))
+	tokenString, err := token.SignedString([]byte(key))
 	return tokenString, err
 }
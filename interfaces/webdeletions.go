@@ -0,0 +1,54 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+
+	"game-tracker/models/result"
+)
+
+func (handler WebserviceHandler) RestoreUser(c *gin.Context) (int, result.User) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.User{}
+	}
+
+	user, err, code := handler.interactor(c).RestoreUser(c.Request.Context(), userId)
+	if err != nil {
+		c.Error(err)
+		return code, result.User{}
+	}
+
+	message := result.User{Id: user.Id, Name: user.Name, LibraryIds: user.LibraryIds,
+		Visibility: user.Visibility, Version: user.Version}
+	setETag(c, user.Version)
+	handler.logger(c).Debug("User restored")
+	return 200, message
+}
+
+func (handler WebserviceHandler) RestoreLibrary(c *gin.Context) (int, result.Library) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Library{}
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Library{}
+	}
+
+	library, err, code := handler.interactor(c).RestoreLibrary(c.Request.Context(), userId, libraryId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Library{}
+	}
+
+	message := result.Library{Id: library.Id, UserId: library.User.Id, Name: library.Name,
+		Description: library.Description, GamesIds: library.GameIds,
+		Visibility: library.Visibility, Version: library.Version}
+	setETag(c, library.Version)
+	handler.logger(c).Debug("Library restored", "libraryId", libraryId)
+	return 200, message
+}
@@ -0,0 +1,145 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+
+	"game-tracker/models/result"
+)
+
+func (handler WebserviceHandler) EditUser(c *gin.Context) (int, result.User) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.User{}
+	}
+	p, err := readPatch(c, "users", userId)
+	if err == nil {
+		err = p.only("name", "visibility")
+	}
+	if err != nil {
+		c.Error(err)
+		return 400, result.User{}
+	}
+	name, err := p.string("name", true)
+	if err != nil {
+		c.Error(err)
+		return 400, result.User{}
+	}
+	visibility, err := p.string("visibility", true)
+	if err != nil {
+		c.Error(err)
+		return 400, result.User{}
+	}
+
+	user, err, code := handler.interactor(c).EditUser(c.Request.Context(), userId, p.version, name, visibility)
+	if err != nil {
+		c.Error(err)
+		return code, result.User{}
+	}
+
+	message := result.User{Id: user.Id, Name: user.Name, LibraryIds: user.LibraryIds,
+		Visibility: user.Visibility, Version: user.Version}
+	setETag(c, user.Version)
+	handler.logger(c).Debug("User edited")
+	return 200, message
+}
+
+func (handler WebserviceHandler) EditLibrary(c *gin.Context) (int, result.Library) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Library{}
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Library{}
+	}
+	p, err := readPatch(c, "libraries", libraryId)
+	if err == nil {
+		err = p.only("name", "description", "visibility")
+	}
+	if err != nil {
+		c.Error(err)
+		return 400, result.Library{}
+	}
+	name, err := p.string("name", false)
+	if err != nil {
+		c.Error(err)
+		return 400, result.Library{}
+	}
+	description, err := p.string("description", false)
+	if err != nil {
+		c.Error(err)
+		return 400, result.Library{}
+	}
+	visibility, err := p.string("visibility", true)
+	if err != nil {
+		c.Error(err)
+		return 400, result.Library{}
+	}
+
+	library, err, code := handler.interactor(c).EditLibrary(c.Request.Context(), userId, libraryId, p.version,
+		name, description, visibility)
+	if err != nil {
+		c.Error(err)
+		return code, result.Library{}
+	}
+
+	message := result.Library{Id: library.Id, UserId: userId, Name: library.Name,
+		Description: library.Description, GamesIds: library.GameIds,
+		Visibility: library.Visibility, Version: library.Version}
+	setETag(c, library.Version)
+	handler.logger(c).Debug("Library edited", "libraryId", libraryId)
+	return 200, message
+}
+
+func (handler WebserviceHandler) EditGame(c *gin.Context) (int, result.Game) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Game{}
+	}
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Game{}
+	}
+	p, err := readPatch(c, "games", gameId)
+	if err == nil {
+		err = p.only("name", "producer", "value")
+	}
+	if err != nil {
+		c.Error(err)
+		return 400, result.Game{}
+	}
+	name, err := p.string("name", true)
+	if err != nil {
+		c.Error(err)
+		return 400, result.Game{}
+	}
+	producer, err := p.string("producer", false)
+	if err != nil {
+		c.Error(err)
+		return 400, result.Game{}
+	}
+	value, err := p.number("value")
+	if err != nil {
+		c.Error(err)
+		return 400, result.Game{}
+	}
+
+	game, err, code := handler.interactor(c).EditGame(c.Request.Context(), userId, gameId, p.version, name,
+		producer, value)
+	if err != nil {
+		c.Error(err)
+		return code, result.Game{}
+	}
+
+	message := result.Game{Id: game.Id, UserId: userId, Name: game.Name,
+		Producer: game.Producer, Value: game.Value, Version: game.Version}
+	setETag(c, game.Version)
+	handler.logger(c).Debug("Game edited", "gameId", gameId)
+	return 200, message
+}
@@ -0,0 +1,200 @@
+package interfaces
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"strconv"
+	"strings"
+
+	"game-tracker/models/responses"
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+const exportFlushEvery = 100
+
+// Formats offered by the exports, the first one is used when the client
+// accepts anything
+var exportFormats = []string{jsonApiMediaType, "application/json", "application/x-ndjson",
+	"text/csv"}
+
+// A gameExporter writes one export format. begin is called once before the
+// first game, end once after the last one.
+type gameExporter interface {
+	begin() error
+	write(entry usecases.LibraryEntry) error
+	end() error
+}
+
+func (handler WebserviceHandler) ExportGames(c *gin.Context) int {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400
+	}
+	libraryId := 0
+	if c.Param("libId") != "" {
+		libraryId, err = strconv.Atoi(c.Param("libId"))
+		if err != nil {
+			c.Error(err)
+			return 400
+		}
+	}
+	format := c.NegotiateFormat(exportFormats...)
+	if format == "" {
+		c.Error(fmt.Errorf("Exports are available as %s", strings.Join(exportFormats, ", ")))
+		return 406
+	}
+
+	exporter := newGameExporter(format, c.Writer, userId, libraryId)
+	started, count := false, 0
+	start := func() error {
+		started = true
+		c.Header("Content-Type", format)
+		c.Header("Content-Disposition", "attachment; filename="+exportFileName(format, libraryId))
+		c.Status(200)
+		return exporter.begin()
+	}
+	err, code := handler.interactor(c).ExportGames(c.Request.Context(), userId, libraryId,
+		func(entry usecases.LibraryEntry) error {
+			if !started {
+				err := start()
+				if err != nil {
+					return err
+				}
+			}
+			count++
+			if count%exportFlushEvery == 0 {
+				c.Writer.Flush()
+			}
+			return exporter.write(entry)
+		})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = exporter.end()
+	}
+	if err != nil {
+		if started {
+			// Too late to report an error, the client gets a truncated export
+			handler.logger(c).Warn("Export interrupted", "error", err)
+			c.Abort()
+			return 200
+		}
+		c.Error(err)
+		return code
+	}
+	handler.logger(c).Debug("Games exported", "games", count)
+	return 200
+}
+
+func exportFileName(format string, libraryId int) string {
+	name := "games"
+	if libraryId != 0 {
+		name = fmt.Sprintf("library-%d", libraryId)
+	}
+	switch format {
+	case "text/csv":
+		return name + ".csv"
+	case "application/x-ndjson":
+		return name + ".jsonl"
+	}
+	return name + ".json"
+}
+
+func newGameExporter(format string, w io.Writer, userId, libraryId int) gameExporter {
+	switch format {
+	case "text/csv":
+		return &csvExporter{writer: csv.NewWriter(w)}
+	case "application/x-ndjson":
+		return &jsonLinesExporter{encoder: json.NewEncoder(w)}
+	}
+	return &jsonApiExporter{w: w, userId: userId, libraryId: libraryId}
+}
+
+// csvExporter writes the columns read back by the imports
+type csvExporter struct {
+	writer *csv.Writer
+}
+
+func (e *csvExporter) begin() error {
+	return e.writer.Write([]string{"libraryId", "gameId", "name", "producer", "value", "tags"})
+}
+
+func (e *csvExporter) write(entry usecases.LibraryEntry) error {
+	return e.writer.Write([]string{strconv.Itoa(entry.LibraryId), strconv.Itoa(entry.Game.Id),
+		entry.Game.Name, entry.Game.Producer, strconv.FormatFloat(entry.Game.Value, 'f', -1, 64),
+		strings.Join(entry.Game.Tags, ";")})
+}
+
+func (e *csvExporter) end() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type jsonLinesExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *jsonLinesExporter) begin() error {
+	return nil
+}
+
+func (e *jsonLinesExporter) write(entry usecases.LibraryEntry) error {
+	return e.encoder.Encode(result.ExportedGame{LibraryId: entry.LibraryId,
+		GameId: entry.Game.Id, Name: entry.Game.Name, Producer: entry.Game.Producer,
+		Value: entry.Game.Value, Tags: entry.Game.Tags})
+}
+
+func (e *jsonLinesExporter) end() error {
+	return nil
+}
+
+// jsonApiExporter writes a single JSON:API document, one resource at a time
+type jsonApiExporter struct {
+	w         io.Writer
+	userId    int
+	libraryId int
+	written   int
+}
+
+func (e *jsonApiExporter) begin() error {
+	self := responses.BaseURL + fmt.Sprintf("/users/%d/export", e.userId)
+	if e.libraryId != 0 {
+		self = responses.BaseURL + fmt.Sprintf("/users/%d/libraries/%d/export", e.userId,
+			e.libraryId)
+	}
+	links, err := json.Marshal(responses.Links{Self: self})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.w, `{"links":%s,"data":[`, links)
+	return err
+}
+
+func (e *jsonApiExporter) write(entry usecases.LibraryEntry) error {
+	game := responses.ViewGame(e.userId, entry.LibraryId, entry.Game.Id, entry.Game.Name,
+		entry.Game.Producer, entry.Game.Value, 0, entry.Game.Tags, 0)
+	resource, err := json.Marshal(game.Data)
+	if err != nil {
+		return err
+	}
+	if e.written > 0 {
+		_, err = io.WriteString(e.w, ",")
+		if err != nil {
+			return err
+		}
+	}
+	e.written++
+	_, err = e.w.Write(resource)
+	return err
+}
+
+func (e *jsonApiExporter) end() error {
+	_, err := io.WriteString(e.w, "]}")
+	return err
+}
@@ -0,0 +1,81 @@
+package interfaces
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+func (handler WebserviceHandler) ShowFeed(c *gin.Context) (int, result.Feed) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Feed{}
+	}
+	cursor, limit, err := readPage(c)
+	if err != nil {
+		c.Error(err)
+		return 400, result.Feed{}
+	}
+
+	events, next, err, code := handler.interactor(c).ShowFeed(c.Request.Context(), c.GetInt("userId"), userId,
+		cursor, limit)
+	if err != nil {
+		c.Error(err)
+		return code, result.Feed{}
+	}
+	return 200, feedMessage(events, limit, next)
+}
+
+func (handler WebserviceHandler) ShowFollowedFeed(c *gin.Context) (int, result.Feed) {
+	cursor, limit, err := readPage(c)
+	if err != nil {
+		c.Error(err)
+		return 400, result.Feed{}
+	}
+
+	events, next, err, code := handler.interactor(c).ShowFollowedFeed(c.Request.Context(), c.GetInt("userId"),
+		cursor, limit)
+	if err != nil {
+		c.Error(err)
+		return code, result.Feed{}
+	}
+	return 200, feedMessage(events, limit, next)
+}
+
+// readPage reads the optional cursor and limit of a feed page
+func readPage(c *gin.Context) (int, int, error) {
+	cursor, limit := 0, 0
+	var err error
+	if value := c.Query("cursor"); value != "" {
+		cursor, err = strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("cursor must be a number")
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("limit must be a number")
+		}
+	}
+	return cursor, limit, nil
+}
+
+func feedMessage(events []usecases.Event, limit, next int) result.Feed {
+	message := result.Feed{Limit: limit, NextCursor: next, Events: []result.Event{}}
+	for _, event := range events {
+		message.Events = append(message.Events, eventMessage(event))
+	}
+	return message
+}
+
+func eventMessage(event usecases.Event) result.Event {
+	return result.Event{Id: event.Id, Kind: event.Kind, UserId: event.UserId,
+		LibraryId: event.LibraryId, GameId: event.GameId, GameName: event.GameName,
+		CreatedAt: event.CreatedAt.Format(time.RFC3339)}
+}
@@ -0,0 +1,208 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+
+	"game-tracker/models/request"
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+func (handler WebserviceHandler) RequestFriend(c *gin.Context) (int, result.Friend) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Friend{}
+	}
+	body := request.Friend{}
+	err = c.BindJSON(&body)
+	if err != nil {
+		return 400, result.Friend{}
+	}
+
+	friendship, err, code := handler.interactor(c).RequestFriend(c.Request.Context(), userId, body.UserId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Friend{}
+	}
+	return code, friendMessage(userId, friendship)
+}
+
+func (handler WebserviceHandler) AcceptFriend(c *gin.Context) (int, result.Friend) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Friend{}
+	}
+	requesterId, err := strconv.Atoi(c.Param("friendId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Friend{}
+	}
+
+	friendship, err, code := handler.interactor(c).AcceptFriend(c.Request.Context(), userId, requesterId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Friend{}
+	}
+	return 200, friendMessage(userId, friendship)
+}
+
+func (handler WebserviceHandler) RemoveFriend(c *gin.Context) int {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400
+	}
+	otherUserId, err := strconv.Atoi(c.Param("friendId"))
+	if err != nil {
+		c.Error(err)
+		return 400
+	}
+
+	err, code := handler.interactor(c).RemoveFriend(c.Request.Context(), userId, otherUserId)
+	if err != nil {
+		c.Error(err)
+		return code
+	}
+	return 204
+}
+
+func (handler WebserviceHandler) ShowFriends(c *gin.Context) (int, result.Friends) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Friends{}
+	}
+
+	friendships, err, code := handler.interactor(c).ShowFriends(c.Request.Context(), c.GetInt("userId"), userId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Friends{}
+	}
+	message := result.Friends{UserId: userId, Friends: []result.Friend{}}
+	for _, friendship := range friendships {
+		message.Friends = append(message.Friends, friendMessage(userId, friendship))
+	}
+	handler.logger(c).Debug("Friends shown", "shownUserId", userId)
+	return 200, message
+}
+
+func (handler WebserviceHandler) FollowUser(c *gin.Context) (int, result.Follow) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Follow{}
+	}
+	followeeId, err := strconv.Atoi(c.Param("followeeId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Follow{}
+	}
+
+	follow, err, code := handler.interactor(c).FollowUser(c.Request.Context(), userId, followeeId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Follow{}
+	}
+	return 200, result.Follow{UserId: follow.FolloweeId,
+		FollowedAt: follow.FollowedAt.Format(time.RFC3339)}
+}
+
+func (handler WebserviceHandler) UnfollowUser(c *gin.Context) int {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400
+	}
+	followeeId, err := strconv.Atoi(c.Param("followeeId"))
+	if err != nil {
+		c.Error(err)
+		return 400
+	}
+
+	err, code := handler.interactor(c).UnfollowUser(c.Request.Context(), userId, followeeId)
+	if err != nil {
+		c.Error(err)
+		return code
+	}
+	return 204
+}
+
+func (handler WebserviceHandler) ShowFollowing(c *gin.Context) (int, result.Follows) {
+	return handler.showFollows(c, false)
+}
+
+func (handler WebserviceHandler) ShowFollowers(c *gin.Context) (int, result.Follows) {
+	return handler.showFollows(c, true)
+}
+
+func (handler WebserviceHandler) showFollows(c *gin.Context, followers bool) (int, result.Follows) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Follows{}
+	}
+
+	follows, err, code := handler.interactor(c).ShowFollows(c.Request.Context(), c.GetInt("userId"), userId,
+		followers)
+	if err != nil {
+		c.Error(err)
+		return code, result.Follows{}
+	}
+	return 200, followsMessage(userId, followers, follows)
+}
+
+func (handler WebserviceHandler) ShowOwningFriends(c *gin.Context) (int, result.GameFriends) {
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.GameFriends{}
+	}
+
+	friends, err, code := handler.interactor(c).ShowOwningFriends(c.Request.Context(), c.GetInt("userId"),
+		gameId)
+	if err != nil {
+		c.Error(err)
+		return code, result.GameFriends{}
+	}
+	message := result.GameFriends{GameId: gameId, Friends: []result.User{}}
+	for _, friend := range friends {
+		message.Friends = append(message.Friends, result.User{Id: friend.Id, Name: friend.Name})
+	}
+	return 200, message
+}
+
+// friendMessage shows the friendship from the side of the user
+func friendMessage(userId int, friendship usecases.Friendship) result.Friend {
+	message := result.Friend{UserId: friendship.Other(userId),
+		RequestedAt: friendship.RequestedAt.Format(time.RFC3339)}
+	switch {
+	case friendship.Status == usecases.FriendshipAccepted:
+		message.Status = "friends"
+	case friendship.UserId == userId:
+		message.Status = "sent"
+	default:
+		message.Status = "received"
+	}
+	if friendship.AcceptedAt != nil {
+		message.AcceptedAt = friendship.AcceptedAt.Format(time.RFC3339)
+	}
+	return message
+}
+
+// followsMessage lists the users the user follows, or its followers
+func followsMessage(userId int, followers bool, follows []usecases.Follow) result.Follows {
+	message := result.Follows{UserId: userId, Followers: followers, Follows: []result.Follow{}}
+	for _, follow := range follows {
+		other := follow.FolloweeId
+		if followers {
+			other = follow.FollowerId
+		}
+		message.Follows = append(message.Follows, result.Follow{UserId: other,
+			FollowedAt: follow.FollowedAt.Format(time.RFC3339)})
+	}
+	return message
+}
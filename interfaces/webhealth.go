@@ -0,0 +1,46 @@
+package interfaces
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+
+	"game-tracker/models/result"
+)
+
+// BuildInfo tells which build is running, set at link time
+type BuildInfo struct {
+	Commit    string
+	BuildTime string
+}
+
+// Healthz answers as long as the process serves requests
+func (handler WebserviceHandler) Healthz(c *gin.Context) (int, result.Health) {
+	return 200, result.Health{Status: "ok"}
+}
+
+// Readyz answers once the database is reachable and migrated up to the
+// version the repositories expect. Newer migrations are fine, they come
+// before the builds using them.
+func (handler WebserviceHandler) Readyz(c *gin.Context) (int, result.Health) {
+	ctx := c.Request.Context()
+	err := handler.Health.Ping(ctx)
+	if err != nil {
+		c.Error(fmt.Errorf("Database unreachable: %v", err))
+		return 503, result.Health{}
+	}
+	version, err := handler.Health.SchemaVersion(ctx)
+	if err != nil {
+		c.Error(fmt.Errorf("Cannot read schema version: %v", err))
+		return 503, result.Health{}
+	}
+	if version < SchemaVersion {
+		c.Error(fmt.Errorf("Schema at version %d, %d expected", version, SchemaVersion))
+		return 503, result.Health{}
+	}
+	return 200, result.Health{Status: "ready", SchemaVersion: version}
+}
+
+func (handler WebserviceHandler) Version(c *gin.Context) (int, result.Version) {
+	return 200, result.Version{Commit: handler.Build.Commit, BuildTime: handler.Build.BuildTime,
+		SchemaVersion: SchemaVersion}
+}
@@ -0,0 +1,188 @@
+package interfaces
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+// maxImportBytes bounds the body of an import, far above what MaxImportRows
+// games take
+const maxImportBytes = 4 << 20
+
+// A game listed in an import, value is a pointer to tell 0 from missing
+type importedGame struct {
+	Name     string   `json:"name"`
+	Producer string   `json:"producer"`
+	Value    *float64 `json:"value"`
+}
+
+func (handler WebserviceHandler) ImportGames(c *gin.Context) (int, result.Import) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Import{}
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Import{}
+	}
+	dryRun := false
+	if value := c.Query("dryRun"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			c.Error(fmt.Errorf("dryRun must be true or false"))
+			return 400, result.Import{}
+		}
+	}
+
+	var rows []usecases.ImportRow
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	if strings.HasPrefix(c.ContentType(), "text/csv") {
+		rows, err = readCsvImport(body)
+	} else {
+		rows, err = readJsonImport(body)
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.Error(fmt.Errorf("Import cannot exceed %d bytes", maxImportBytes))
+		return 413, result.Import{}
+	}
+	if err != nil {
+		c.Error(err)
+		return 400, result.Import{}
+	}
+
+	results, err, code := handler.interactor(c).ImportGames(c.Request.Context(), userId, libraryId, rows, dryRun)
+	if err != nil {
+		c.Error(err)
+		return code, result.Import{}
+	}
+
+	message := result.Import{UserId: userId, LibraryId: libraryId, DryRun: dryRun}
+	for _, row := range results {
+		message.Rows = append(message.Rows, result.ImportRow{Line: row.Line, Name: row.Name,
+			GameId: row.GameId, Status: row.Status, Reason: row.Reason})
+	}
+	handler.logger(c).Debug("Rows imported", "libraryId", libraryId, "rows", len(results))
+	return 200, message
+}
+
+// readCsvImport reads a CSV whose header names the name, producer and value
+// columns, in any order. Lines are numbered as in the file. Reading stops one
+// row past MaxImportRows, enough for the import to be refused.
+func readCsvImport(body io.Reader) ([]usecases.ImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range []string{"name", "producer", "value"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the '%s' column", column)
+		}
+	}
+
+	var rows []usecases.ImportRow
+	for len(rows) <= usecases.MaxImportRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		// Only a malformed line is a problem of its row, the body itself
+		// failing ends the import
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, usecases.ImportRow{Line: parseErr.StartLine, Problem: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		row := usecases.ImportRow{Line: line}
+		field := func(column string) string {
+			if columns[column] < len(record) {
+				return record[columns[column]]
+			}
+			return ""
+		}
+		row.Name = field("name")
+		row.Producer = field("producer")
+		value := strings.TrimSpace(field("value"))
+		if value == "" {
+			row.Problem = "Value is required"
+		} else if row.Value, err = strconv.ParseFloat(value, 64); err != nil {
+			row.Problem = fmt.Sprintf("Value '%s' is not a number", value)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// readJsonImport reads a JSON array of games, numbering them from 1. Like
+// readCsvImport it stops one game past MaxImportRows.
+func readJsonImport(body io.Reader) ([]usecases.ImportRow, error) {
+	decoder := json.NewDecoder(body)
+	token, err := decoder.Token()
+	if delim, ok := token.(json.Delim); err == nil && (!ok || delim != '[') {
+		err = fmt.Errorf("expected an array")
+	}
+	if err != nil {
+		return nil, importJsonError(err)
+	}
+
+	var rows []usecases.ImportRow
+	for decoder.More() && len(rows) <= usecases.MaxImportRows {
+		var element json.RawMessage
+		err = decoder.Decode(&element)
+		if err != nil {
+			return nil, importJsonError(err)
+		}
+		row := usecases.ImportRow{Line: len(rows) + 1}
+		game := importedGame{}
+		err = json.Unmarshal(element, &game)
+		switch {
+		case err != nil:
+			row.Problem = "Not a game object"
+		case game.Value == nil:
+			row.Problem = "Value is required"
+		default:
+			row.Name, row.Producer, row.Value = game.Name, game.Producer, *game.Value
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) <= usecases.MaxImportRows {
+		_, err = decoder.Token()
+		if err != nil {
+			return nil, importJsonError(err)
+		}
+	}
+	return rows, nil
+}
+
+// importJsonError explains a body that is not an array of games, keeping the
+// error of a body too large to be told apart
+func importJsonError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return err
+	}
+	return fmt.Errorf("Import must be a JSON array of games: %v", err)
+}
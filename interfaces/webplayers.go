@@ -0,0 +1,80 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+
+	"game-tracker/domain"
+	"game-tracker/models/request"
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+func (handler WebserviceHandler) ShowPlayer(c *gin.Context) (int, result.Player) {
+	playerId, err := strconv.Atoi(c.Param("playerId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Player{}
+	}
+
+	player, users, err, code := handler.interactor(c).ShowPlayer(c.Request.Context(), c.GetInt("userId"), playerId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Player{}
+	}
+	handler.logger(c).Debug("Player shown", "playerId", playerId)
+	return 200, playerMessage(player, users)
+}
+
+func (handler WebserviceHandler) RenamePlayer(c *gin.Context) (int, result.Player) {
+	playerId, err := strconv.Atoi(c.Param("playerId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Player{}
+	}
+	body := request.Player{}
+	err = c.BindJSON(&body)
+	if err != nil {
+		return 400, result.Player{}
+	}
+
+	player, err, code := handler.interactor(c).RenamePlayer(c.Request.Context(), c.GetInt("userId"), playerId,
+		body.Name)
+	if err != nil {
+		c.Error(err)
+		return code, result.Player{}
+	}
+	return 200, result.Player{Id: player.Id, Name: player.Name}
+}
+
+func (handler WebserviceHandler) MergePlayers(c *gin.Context) (int, result.Player) {
+	playerId, err := strconv.Atoi(c.Param("playerId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Player{}
+	}
+	loginInfo := request.LoginInfo{}
+	err = c.BindJSON(&loginInfo)
+	if err != nil {
+		return 400, result.Player{}
+	}
+
+	player, users, err, code := handler.interactor(c).MergePlayers(c.Request.Context(), c.GetInt("userId"),
+		playerId, loginInfo.Username, loginInfo.Password)
+	if err != nil {
+		c.Error(err)
+		return code, result.Player{}
+	}
+	return 200, playerMessage(player, users)
+}
+
+func playerMessage(player domain.Player, users []usecases.User) result.Player {
+	message := result.Player{Id: player.Id, Name: player.Name, AccountIds: []int{},
+		Accounts: []result.User{}}
+	for _, user := range users {
+		message.AccountIds = append(message.AccountIds, user.Id)
+		message.Accounts = append(message.Accounts, result.User{Id: user.Id, Name: user.Name,
+			LibraryIds: user.LibraryIds, Visibility: user.Visibility, Version: user.Version})
+	}
+	return message
+}
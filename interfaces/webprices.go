@@ -0,0 +1,63 @@
+package interfaces
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+
+	"game-tracker/models/result"
+)
+
+func (handler WebserviceHandler) ShowPrices(c *gin.Context) (int, result.Prices) {
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Prices{}
+	}
+	from, err := parseDate(c.Query("from"), false)
+	if err != nil {
+		c.Error(err)
+		return 400, result.Prices{}
+	}
+	to, err := parseDate(c.Query("to"), true)
+	if err != nil {
+		c.Error(err)
+		return 400, result.Prices{}
+	}
+
+	game, prices, err, code := handler.interactor(c).ShowPrices(c.Request.Context(), gameId, from, to)
+	if err != nil {
+		c.Error(err)
+		return code, result.Prices{}
+	}
+
+	message := result.Prices{GameId: game.Id, Value: game.Value, LowestValue: game.LowestValue}
+	for _, price := range prices {
+		message.Prices = append(message.Prices, result.Price{Value: price.Value,
+			Source: price.Source, RecordedAt: price.RecordedAt.Format(time.RFC3339)})
+	}
+	handler.logger(c).Debug("Prices shown", "gameId", gameId)
+	return 200, message
+}
+
+// parseDate reads a date given either as a day (2006-01-02) or a full RFC 3339
+// timestamp. An empty string is the zero time. A day used as the end of a range
+// includes the whole day.
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err == nil {
+		if endOfDay {
+			date = date.Add(24*time.Hour - time.Nanosecond)
+		}
+		return date, nil
+	}
+	date, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid date '%s'", value)
+	}
+	return date, nil
+}
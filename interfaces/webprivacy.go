@@ -0,0 +1,183 @@
+package interfaces
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+// ExportPersonalData sends a zip archive with one JSON file per kind of data
+// held about the user.
+func (handler WebserviceHandler) ExportPersonalData(c *gin.Context) int {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400
+	}
+
+	data, err, code := handler.interactor(c).ExportPersonalData(c.Request.Context(), userId)
+	if err != nil {
+		c.Error(err)
+		return code
+	}
+
+	user := data.User
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"user.json", struct {
+			result.User
+			Info string `json:"userInfo"`
+		}{result.User{Id: user.Id, Name: user.Name, LibraryIds: user.LibraryIds,
+			Visibility: user.Visibility, Version: user.Version}, user.PersonalInfo}},
+		{"player.json", result.Player{Id: user.Player.Id, Name: user.Player.Name,
+			AccountIds: data.AccountIds}},
+		{"libraries.json", personalLibraries(data.Libraries)},
+		{"games.json", personalGames(user.Id, data)},
+		{"wishlist.json", personalWishlist(data)},
+		{"notifications.json", personalInbox(data)},
+		{"achievements.json", personalAchievements(user.Id, data)},
+		{"friends.json", personalFriends(data)},
+		{"following.json", followsMessage(user.Id, false, data.Following)},
+		{"followers.json", followsMessage(user.Id, true, data.Followers)},
+		{"activity.json", personalEvents(data)},
+		{"shares.json", personalShares(data)},
+		{"audit.json", personalAudit(data)},
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=user-%d.zip", user.Id))
+	c.Status(200)
+	archive := zip.NewWriter(c.Writer)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err == nil {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(file.content)
+		}
+		if err != nil {
+			// The archive has started, the client gets a truncated one
+			handler.logger(c).Warn("Personal data export interrupted", "error", err)
+			c.Abort()
+			return 200
+		}
+	}
+	err = archive.Close()
+	if err != nil {
+		handler.logger(c).Warn("Personal data export interrupted", "error", err)
+		c.Abort()
+	}
+	return 200
+}
+
+func (handler WebserviceHandler) EraseUser(c *gin.Context) (int, result.Erasure) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Erasure{}
+	}
+
+	erasure, err, code := handler.interactor(c).EraseUser(c.Request.Context(), userId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Erasure{}
+	}
+	return 200, result.Erasure{Id: erasure.Id, UserId: erasure.UserId,
+		PlayerId: erasure.PlayerId, PlayerErased: erasure.PlayerErased,
+		ErasedAt: erasure.ErasedAt.Format(time.RFC3339)}
+}
+
+func personalLibraries(libraries []usecases.Library) []result.Library {
+	messages := []result.Library{}
+	for _, library := range libraries {
+		messages = append(messages, result.Library{Id: library.Id, UserId: library.User.Id,
+			Name: library.Name, Description: library.Description, GamesIds: library.GameIds,
+			Visibility: library.Visibility, Version: library.Version})
+	}
+	return messages
+}
+
+func personalGames(userId int, data usecases.PersonalData) []result.Game {
+	messages := []result.Game{}
+	for _, entry := range data.Games {
+		messages = append(messages, result.Game{Id: entry.Game.Id, LibraryId: entry.LibraryId,
+			UserId: userId, Name: entry.Game.Name, Producer: entry.Game.Producer,
+			Value: entry.Game.Value, Tags: entry.Game.Tags})
+	}
+	return messages
+}
+
+func personalWishlist(data usecases.PersonalData) result.Wishlist {
+	message := result.Wishlist{UserId: data.User.Id, Entries: []result.WishlistEntry{}}
+	for _, entry := range data.Wishlist {
+		message.Entries = append(message.Entries, result.WishlistEntry{GameId: entry.GameId,
+			UserId: entry.UserId, TargetPrice: entry.TargetPrice,
+			AddedAt: entry.AddedAt.Format(time.RFC3339)})
+	}
+	return message
+}
+
+func personalInbox(data usecases.PersonalData) result.Inbox {
+	message := result.Inbox{UserId: data.User.Id, Notifications: []result.Notification{}}
+	for _, notification := range data.Notifications {
+		message.Notifications = append(message.Notifications, result.Notification{
+			Id: notification.Id, UserId: notification.UserId, GameId: notification.GameId,
+			Message: notification.Message, Value: notification.Value,
+			CreatedAt: notification.CreatedAt.Format(time.RFC3339)})
+	}
+	return message
+}
+
+func personalAchievements(userId int, data usecases.PersonalData) []result.Achievement {
+	messages := []result.Achievement{}
+	for _, achievement := range data.Achievements {
+		messages = append(messages, result.Achievement{Id: achievement.Id,
+			GameId: achievement.GameId, UserId: userId, Name: achievement.Name,
+			Description: achievement.Description, Points: achievement.Points,
+			Hidden: achievement.Hidden, Unlocked: true,
+			UnlockedAt: achievement.UnlockedAt.Format(time.RFC3339)})
+	}
+	return messages
+}
+
+func personalFriends(data usecases.PersonalData) result.Friends {
+	message := result.Friends{UserId: data.User.Id, Friends: []result.Friend{}}
+	for _, friendship := range data.Friendships {
+		message.Friends = append(message.Friends, friendMessage(data.User.Id, friendship))
+	}
+	return message
+}
+
+func personalEvents(data usecases.PersonalData) []result.Event {
+	messages := []result.Event{}
+	for _, event := range data.Events {
+		messages = append(messages, eventMessage(event))
+	}
+	return messages
+}
+
+func personalShares(data usecases.PersonalData) []result.Share {
+	messages := []result.Share{}
+	for _, share := range data.Shares {
+		message := shareMessage(share)
+		message.UserId = data.User.Id
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+func personalAudit(data usecases.PersonalData) []result.AuditEntry {
+	messages := []result.AuditEntry{}
+	for _, entry := range data.AuditEntries {
+		messages = append(messages, auditEntryMessage(entry))
+	}
+	return messages
+}
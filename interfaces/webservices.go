@@ -1,9 +1,9 @@
 package interfaces
 
 import (
-	"fmt"
 	"github.com/gin-gonic/gin"
 	"strconv"
+	"time"
 
 	"game-tracker/domain"
 	"game-tracker/models/request"
@@ -28,6 +28,31 @@ type ProfileInteractor interface {
 
 type WebserviceHandler struct {
 	ProfileInteractor usecases.ProfileInteractor
+	Health            *DbHealthRepo
+	Build             BuildInfo
+	TokenKey          string        //Signs the login tokens
+	TokenLifetime     time.Duration //Until the login tokens expire
+}
+
+// interactor gives the use cases a logger carrying the fields of the request
+func (handler WebserviceHandler) interactor(c *gin.Context) *usecases.ProfileInteractor {
+	interactor := handler.ProfileInteractor
+	interactor.Loggr = handler.logger(c)
+	interactor.Origin = c.ClientIP() //Forwarded headers count from trusted proxies only
+	return &interactor
+}
+
+// logger is the logger of the request, with the id of the user once the
+// token is checked
+func (handler WebserviceHandler) logger(c *gin.Context) usecases.LoggerRepository {
+	logger := handler.ProfileInteractor.Loggr
+	if requestLogger, ok := c.Get("logger"); ok {
+		logger = requestLogger.(usecases.LoggerRepository)
+	}
+	if userId := c.GetInt("userId"); userId != 0 {
+		logger = logger.With("userId", userId)
+	}
+	return logger
 }
 
 func (handler WebserviceHandler) AddUser(c *gin.Context) (int, result.UserAdd) {
@@ -38,14 +63,14 @@ func (handler WebserviceHandler) AddUser(c *gin.Context) (int, result.UserAdd) {
 	}
 
 	player := domain.Player{Id: user.PlayerId, Name: user.PlayerName}
-	id, err, code := handler.ProfileInteractor.AddUser(player, user.Name, user.Password)
+	added, err, code := handler.interactor(c).AddUser(c.Request.Context(), player, user.Name, user.Password)
 	if err != nil {
 		c.Error(err)
 		return code, result.UserAdd{}
 	}
 
-	message := result.UserAdd{Id: id, Name: user.Name}
-	fmt.Printf("Created user #%d\n", id)
+	message := result.UserAdd{Id: added.Id, Name: added.Name, PlayerId: added.Player.Id}
+	handler.logger(c).Debug("User created", "addedUserId", added.Id)
 	return 201, message
 }
 
@@ -56,19 +81,22 @@ func (handler WebserviceHandler) ShowUser(c *gin.Context) (int, result.User) {
 		return 400, result.User{}
 	}
 
-	name, libraryIds, err, code := handler.ProfileInteractor.ShowUser(userId)
+	user, err, code := handler.interactor(c).ShowUser(c.Request.Context(), c.GetInt("userId"), userId)
 	if err != nil {
 		c.Error(err)
 		return code, result.User{}
 	}
 
 	var message result.User
-	message.Name = name
+	message.Name = user.Name
 	message.Id = userId
-	for _, libraryId := range libraryIds {
+	message.Visibility = user.Visibility
+	message.Version = user.Version
+	for _, libraryId := range user.LibraryIds {
 		message.LibraryIds = append(message.LibraryIds, libraryId)
 	}
-	fmt.Printf("Printed user #%d\n", userId)
+	setETag(c, user.Version)
+	handler.logger(c).Debug("User shown", "shownUserId", userId)
 	return 200, message
 }
 
@@ -79,14 +107,14 @@ func (handler WebserviceHandler) RemoveUser(c *gin.Context) (int, result.UserDel
 		return 400, result.UserDelete{}
 	}
 
-	err, code := handler.ProfileInteractor.RemoveUser(userId)
+	err, code := handler.interactor(c).RemoveUser(c.Request.Context(), userId)
 	if err != nil {
 		c.Error(err)
 		return code, result.UserDelete{}
 	}
 
 	message := result.UserDelete{Id: userId}
-	fmt.Printf("Deleted user #%d\n", userId)
+	handler.logger(c).Debug("User deleted")
 	return 200, message
 }
 
@@ -97,14 +125,14 @@ func (handler WebserviceHandler) ShowUserInfo(c *gin.Context) (int, result.UserI
 		return 400, result.UserInfo{}
 	}
 
-	info, err, code := handler.ProfileInteractor.ShowUserInfo(userId)
+	info, err, code := handler.interactor(c).ShowUserInfo(c.Request.Context(), c.GetInt("userId"), userId)
 	if err != nil {
 		c.Error(err)
 		return code, result.UserInfo{}
 	}
 
 	message := result.UserInfo{Id: userId, Info: info}
-	fmt.Printf("Printed info of user #%d\n", userId)
+	handler.logger(c).Debug("User info shown", "shownUserId", userId)
 	return 200, message
 }
 
@@ -120,14 +148,14 @@ func (handler WebserviceHandler) EditUserInfo(c *gin.Context) (int, result.UserI
 		return 400, result.UserInfo{}
 	}
 
-	err, code := handler.ProfileInteractor.EditUserInfo(userId, userInfo.Info)
+	err, code := handler.interactor(c).EditUserInfo(c.Request.Context(), userId, userInfo.Info)
 	if err != nil {
 		c.Error(err)
 		return code, result.UserInfo{}
 	}
 
 	message := result.UserInfo{Id: userId, Info: userInfo.Info}
-	fmt.Printf("Editted info of user #%d\n", userId)
+	handler.logger(c).Debug("User info edited")
 	return 200, message
 }
 
@@ -137,14 +165,24 @@ func (handler WebserviceHandler) AddLibrary(c *gin.Context) (int, result.Library
 		c.Error(err)
 		return 400, result.LibraryAdd{}
 	}
-	id, err, code := handler.ProfileInteractor.AddLibrary(userId)
+	// The body is optional, libraries can stay unnamed
+	library := request.Library{}
+	if c.Request.ContentLength != 0 {
+		err = c.BindJSON(&library)
+		if err != nil {
+			return 400, result.LibraryAdd{}
+		}
+	}
+	id, err, code := handler.interactor(c).AddLibrary(c.Request.Context(), userId, library.Name, library.Description,
+		library.Visibility)
 	if err != nil {
 		c.Error(err)
 		return code, result.LibraryAdd{}
 	}
 
-	message := result.LibraryAdd{Id: id, UserId: userId}
-	fmt.Printf("Added library #%d\n", id)
+	message := result.LibraryAdd{Id: id, UserId: userId, Name: library.Name,
+		Description: library.Description}
+	handler.logger(c).Debug("Library added", "libraryId", id)
 	return 201, message
 }
 
@@ -160,7 +198,8 @@ func (handler WebserviceHandler) ShowLibrary(c *gin.Context) (int, result.Librar
 		return 400, result.Library{}
 	}
 
-	gameIds, err, code := handler.ProfileInteractor.ShowLibrary(userId, libraryId)
+	library, err, code := handler.interactor(c).ShowLibrary(c.Request.Context(), c.GetInt("userId"), userId,
+		libraryId)
 	if err != nil {
 		c.Error(err)
 		return code, result.Library{}
@@ -169,10 +208,15 @@ func (handler WebserviceHandler) ShowLibrary(c *gin.Context) (int, result.Librar
 	var message result.Library
 	message.Id = libraryId
 	message.UserId = userId
-	for _, gameId := range gameIds {
+	message.Name = library.Name
+	message.Description = library.Description
+	message.Visibility = library.Visibility
+	message.Version = library.Version
+	for _, gameId := range library.GameIds {
 		message.GamesIds = append(message.GamesIds, gameId)
 	}
-	fmt.Printf("Printed library #%d\n", libraryId)
+	setETag(c, library.Version)
+	handler.logger(c).Debug("Library shown", "libraryId", libraryId)
 	return 200, message
 }
 
@@ -188,14 +232,14 @@ func (handler WebserviceHandler) RemoveLibrary(c *gin.Context) (int, result.Libr
 		return 400, result.LibraryDelete{}
 	}
 
-	err, code := handler.ProfileInteractor.RemoveLibrary(userId, libraryId)
+	err, code := handler.interactor(c).RemoveLibrary(c.Request.Context(), userId, libraryId)
 	if err != nil {
 		c.Error(err)
 		return code, result.LibraryDelete{}
 	}
 
 	message := result.LibraryDelete{Id: libraryId}
-	fmt.Printf("Deleted library #%d\n", libraryId)
+	handler.logger(c).Debug("Library deleted", "libraryId", libraryId)
 	return 200, message
 }
 
@@ -216,15 +260,18 @@ func (handler WebserviceHandler) ShowGame(c *gin.Context) (int, result.Game) {
 		return 400, result.Game{}
 	}
 
-	game, err, code := handler.ProfileInteractor.ShowGame(userId, libraryId, gameId)
+	game, err, code := handler.interactor(c).ShowGame(c.Request.Context(), c.GetInt("userId"), userId, libraryId,
+		gameId)
 	if err != nil {
 		c.Error(err)
 		return code, result.Game{}
 	}
 
 	message := result.Game{Id: game.Id, LibraryId: libraryId, UserId: userId,
-		Name: game.Name, Producer: game.Producer, Value: game.Value}
-	fmt.Printf("Printed game #%d\n", game.Id)
+		Name: game.Name, Producer: game.Producer, Value: game.Value,
+		LowestValue: game.LowestValue, Tags: game.Tags, Version: game.Version}
+	setETag(c, game.Version)
+	handler.logger(c).Debug("Game shown", "gameId", game.Id)
 	return 200, message
 }
 
@@ -245,7 +292,7 @@ func (handler WebserviceHandler) AddGame(c *gin.Context) (int, result.Game) {
 		return 400, result.Game{}
 	}
 
-	id, err, code := handler.ProfileInteractor.AddGame(userId, libraryId, game.Name, game.Producer, game.Value)
+	id, err, code := handler.interactor(c).AddGame(c.Request.Context(), userId, libraryId, game.Name, game.Producer, game.Value)
 	if err != nil {
 		c.Error(err)
 		return code, result.Game{}
@@ -253,7 +300,7 @@ func (handler WebserviceHandler) AddGame(c *gin.Context) (int, result.Game) {
 
 	message := result.Game{Id: id, LibraryId: libraryId, UserId: userId, Name: game.Name,
 		Producer: game.Producer, Value: game.Value}
-	fmt.Printf("Added game #%d\n", id)
+	handler.logger(c).Debug("Game added", "gameId", id)
 	return 201, message
 }
 
@@ -274,14 +321,14 @@ func (handler WebserviceHandler) PickGame(c *gin.Context) (int, result.GameToLib
 		return 400, result.GameToLib{}
 	}
 
-	err, code := handler.ProfileInteractor.PickGame(userId, libraryId, gameId)
+	err, code := handler.interactor(c).PickGame(c.Request.Context(), userId, libraryId, gameId)
 	if err != nil {
 		c.Error(err)
 		return code, result.GameToLib{}
 	}
 
 	message := result.GameToLib{Id: gameId, LibraryId: libraryId, UserId: userId}
-	fmt.Printf("Added game #%d\n", gameId)
+	handler.logger(c).Debug("Game added", "gameId", gameId)
 	return 201, message
 }
 
@@ -302,13 +349,13 @@ func (handler WebserviceHandler) RemoveGame(c *gin.Context) (int, result.GameToL
 		return 400, result.GameToLib{}
 	}
 
-	err, code := handler.ProfileInteractor.RemoveGame(userId, libraryId, gameId)
+	err, code := handler.interactor(c).RemoveGame(c.Request.Context(), userId, libraryId, gameId)
 	if err != nil {
 		c.Error(err)
 		return code, result.GameToLib{}
 	}
 
 	message := result.GameToLib{Id: gameId, LibraryId: libraryId}
-	fmt.Printf("Deleted game #%d\n", gameId)
+	handler.logger(c).Debug("Game deleted", "gameId", gameId)
 	return 200, message
 }
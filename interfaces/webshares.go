@@ -0,0 +1,105 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+func (handler WebserviceHandler) ShareLibrary(c *gin.Context) (int, result.Share) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Share{}
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Share{}
+	}
+
+	share, err, code := handler.interactor(c).ShareLibrary(c.Request.Context(), userId, libraryId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Share{}
+	}
+	message := shareMessage(share)
+	message.UserId = userId
+	return code, message
+}
+
+func (handler WebserviceHandler) ShowShares(c *gin.Context) (int, result.Shares) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Shares{}
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Shares{}
+	}
+
+	shares, err, code := handler.interactor(c).ShowShares(c.Request.Context(), userId, libraryId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Shares{}
+	}
+	message := result.Shares{UserId: userId, LibraryId: libraryId, Shares: []result.Share{}}
+	for _, share := range shares {
+		message.Shares = append(message.Shares, shareMessage(share))
+	}
+	handler.logger(c).Debug("Shares shown", "libraryId", libraryId)
+	return 200, message
+}
+
+func (handler WebserviceHandler) RevokeShare(c *gin.Context) int {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400
+	}
+	shareId, err := strconv.Atoi(c.Param("shareId"))
+	if err != nil {
+		c.Error(err)
+		return 400
+	}
+
+	err, code := handler.interactor(c).RevokeShare(c.Request.Context(), userId, libraryId, shareId)
+	if err != nil {
+		c.Error(err)
+		return code
+	}
+	return 204
+}
+
+func (handler WebserviceHandler) ShowSharedLibrary(c *gin.Context) (int, result.Library) {
+	library, err, code := handler.interactor(c).ShowSharedLibrary(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err)
+		return code, result.Library{}
+	}
+
+	message := result.Library{Id: library.Id, UserId: library.User.Id, Name: library.Name,
+		Description: library.Description, GamesIds: library.GameIds,
+		Visibility: library.Visibility, Version: library.Version}
+	handler.logger(c).Debug("Shared library shown", "libraryId", library.Id)
+	return 200, message
+}
+
+func shareMessage(share usecases.Share) result.Share {
+	message := result.Share{Id: share.Id, LibraryId: share.LibraryId, Token: share.Token,
+		CreatedAt: share.CreatedAt.Format(time.RFC3339)}
+	if share.RevokedAt != nil {
+		message.RevokedAt = share.RevokedAt.Format(time.RFC3339)
+	}
+	return message
+}
@@ -0,0 +1,111 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+
+	"game-tracker/models/request"
+	"game-tracker/models/result"
+)
+
+func (handler WebserviceHandler) TagGame(c *gin.Context) (int, result.Tags) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Tags{}
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Tags{}
+	}
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Tags{}
+	}
+	tag := request.Tag{}
+	err = c.BindJSON(&tag)
+	if err != nil {
+		return 400, result.Tags{}
+	}
+
+	tags, err, code := handler.interactor(c).TagGame(c.Request.Context(), userId, libraryId, gameId, tag.Tag)
+	if err != nil {
+		c.Error(err)
+		return code, result.Tags{}
+	}
+
+	message := result.Tags{UserId: userId, LibraryId: libraryId, GameId: gameId, Tags: tags}
+	handler.logger(c).Debug("Game tagged", "gameId", gameId)
+	return 201, message
+}
+
+func (handler WebserviceHandler) UntagGame(c *gin.Context) (int, result.Tags) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Tags{}
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Tags{}
+	}
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Tags{}
+	}
+
+	err, code := handler.interactor(c).UntagGame(c.Request.Context(), userId, libraryId, gameId, c.Param("tag"))
+	if err != nil {
+		c.Error(err)
+		return code, result.Tags{}
+	}
+
+	message := result.Tags{UserId: userId, LibraryId: libraryId, GameId: gameId}
+	handler.logger(c).Debug("Game untagged", "gameId", gameId)
+	return 200, message
+}
+
+func (handler WebserviceHandler) ShowUserGames(c *gin.Context) (int, result.UserGames) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.UserGames{}
+	}
+	tag := c.Query("tag")
+
+	entries, err, code := handler.interactor(c).ShowUserGames(c.Request.Context(), userId, tag)
+	if err != nil {
+		c.Error(err)
+		return code, result.UserGames{}
+	}
+
+	message := result.UserGames{UserId: userId, Tag: tag}
+	for _, entry := range entries {
+		message.Games = append(message.Games, result.Game{Id: entry.Game.Id,
+			LibraryId: entry.LibraryId, UserId: userId, Name: entry.Game.Name,
+			Producer: entry.Game.Producer, Value: entry.Game.Value, Tags: entry.Game.Tags})
+	}
+	handler.logger(c).Debug("User games shown")
+	return 200, message
+}
+
+func (handler WebserviceHandler) SuggestTags(c *gin.Context) (int, result.Tags) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Tags{}
+	}
+
+	tags, err, code := handler.interactor(c).SuggestTags(c.Request.Context(), userId, c.Query("prefix"))
+	if err != nil {
+		c.Error(err)
+		return code, result.Tags{}
+	}
+
+	message := result.Tags{UserId: userId, Tags: tags}
+	return 200, message
+}
@@ -0,0 +1,110 @@
+package interfaces
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+
+	"game-tracker/models/request"
+	"game-tracker/models/result"
+	"game-tracker/usecases"
+)
+
+func (handler WebserviceHandler) MoveGame(c *gin.Context) (int, result.Transfers) {
+	return handler.transferGame(c, false)
+}
+
+func (handler WebserviceHandler) CopyGame(c *gin.Context) (int, result.Transfers) {
+	return handler.transferGame(c, true)
+}
+
+func (handler WebserviceHandler) MoveGames(c *gin.Context) (int, result.Transfers) {
+	return handler.transferGames(c, false)
+}
+
+func (handler WebserviceHandler) CopyGames(c *gin.Context) (int, result.Transfers) {
+	return handler.transferGames(c, true)
+}
+
+func (handler WebserviceHandler) transferGame(c *gin.Context, keep bool) (int, result.Transfers) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Transfers{}
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Transfers{}
+	}
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Transfers{}
+	}
+	transfer := request.Transfer{}
+	err = c.BindJSON(&transfer)
+	if err != nil {
+		return 400, result.Transfers{}
+	}
+
+	transfers, err, code := handler.interactor(c).TransferGames(c.Request.Context(), userId, libraryId,
+		transfer.TargetLibraryId, []int{gameId}, keep)
+	if err != nil {
+		c.Error(err)
+		return code, result.Transfers{}
+	}
+	switch transfers[0].Status {
+	case usecases.TransferConflict:
+		c.Error(fmt.Errorf("Game already existed in library #%d", transfer.TargetLibraryId))
+		return 409, result.Transfers{}
+	case usecases.TransferMissing:
+		c.Error(fmt.Errorf("Game #%d is not in library #%d", gameId, libraryId))
+		return 404, result.Transfers{}
+	}
+
+	message := viewTransfers(userId, libraryId, transfer.TargetLibraryId, transfers)
+	handler.logger(c).Debug("Game transferred", "gameId", gameId,
+		"targetLibraryId", transfer.TargetLibraryId)
+	return 200, message
+}
+
+func (handler WebserviceHandler) transferGames(c *gin.Context, keep bool) (int, result.Transfers) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Transfers{}
+	}
+	libraryId, err := strconv.Atoi(c.Param("libId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Transfers{}
+	}
+	transfer := request.BatchTransfer{}
+	err = c.BindJSON(&transfer)
+	if err != nil {
+		return 400, result.Transfers{}
+	}
+
+	transfers, err, code := handler.interactor(c).TransferGames(c.Request.Context(), userId, libraryId,
+		transfer.TargetLibraryId, transfer.GameIds, keep)
+	if err != nil {
+		c.Error(err)
+		return code, result.Transfers{}
+	}
+
+	message := viewTransfers(userId, libraryId, transfer.TargetLibraryId, transfers)
+	handler.logger(c).Debug("Games transferred", "games", len(transfers),
+		"targetLibraryId", transfer.TargetLibraryId)
+	return 200, message
+}
+
+func viewTransfers(userId, libraryId, targetLibraryId int, transfers []usecases.Transfer) result.Transfers {
+	message := result.Transfers{UserId: userId, LibraryId: libraryId,
+		TargetLibraryId: targetLibraryId}
+	for _, transfer := range transfers {
+		message.Transfers = append(message.Transfers,
+			result.Transfer{GameId: transfer.GameId, Status: transfer.Status})
+	}
+	return message
+}
@@ -0,0 +1,141 @@
+package interfaces
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+
+	"game-tracker/models/request"
+	"game-tracker/models/result"
+)
+
+func (handler WebserviceHandler) AddToWishlist(c *gin.Context) (int, result.WishlistEntry) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.WishlistEntry{}
+	}
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.WishlistEntry{}
+	}
+	// The body is optional, an empty one means no price target
+	wish := request.WishlistEntry{}
+	if c.Request.ContentLength != 0 {
+		err = c.BindJSON(&wish)
+		if err != nil {
+			return 400, result.WishlistEntry{}
+		}
+	}
+
+	entry, err, code := handler.interactor(c).AddToWishlist(c.Request.Context(), userId, gameId, wish.TargetPrice)
+	if err != nil {
+		c.Error(err)
+		return code, result.WishlistEntry{}
+	}
+
+	message := result.WishlistEntry{GameId: gameId, UserId: userId,
+		TargetPrice: entry.TargetPrice, AddedAt: entry.AddedAt.Format(time.RFC3339)}
+	handler.logger(c).Debug("Game added to wishlist", "gameId", gameId)
+	return 201, message
+}
+
+func (handler WebserviceHandler) ShowWishlist(c *gin.Context) (int, result.Wishlist) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Wishlist{}
+	}
+
+	entries, err, code := handler.interactor(c).ShowWishlist(c.Request.Context(), userId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Wishlist{}
+	}
+
+	message := result.Wishlist{UserId: userId}
+	for _, entry := range entries {
+		message.Entries = append(message.Entries, result.WishlistEntry{GameId: entry.GameId,
+			UserId: userId, TargetPrice: entry.TargetPrice,
+			AddedAt: entry.AddedAt.Format(time.RFC3339)})
+	}
+	handler.logger(c).Debug("Wishlist shown")
+	return 200, message
+}
+
+func (handler WebserviceHandler) RemoveFromWishlist(c *gin.Context) (int, result.WishlistEntry) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.WishlistEntry{}
+	}
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.WishlistEntry{}
+	}
+
+	err, code := handler.interactor(c).RemoveFromWishlist(c.Request.Context(), userId, gameId)
+	if err != nil {
+		c.Error(err)
+		return code, result.WishlistEntry{}
+	}
+
+	message := result.WishlistEntry{GameId: gameId, UserId: userId}
+	handler.logger(c).Debug("Game removed from wishlist", "gameId", gameId)
+	return 200, message
+}
+
+func (handler WebserviceHandler) ShowInbox(c *gin.Context) (int, result.Inbox) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Inbox{}
+	}
+
+	notifications, err, code := handler.interactor(c).ShowInbox(c.Request.Context(), userId)
+	if err != nil {
+		c.Error(err)
+		return code, result.Inbox{}
+	}
+
+	message := result.Inbox{UserId: userId}
+	for _, notification := range notifications {
+		message.Notifications = append(message.Notifications, result.Notification{
+			Id: notification.Id, UserId: userId, GameId: notification.GameId,
+			Message: notification.Message, Value: notification.Value,
+			CreatedAt: notification.CreatedAt.Format(time.RFC3339)})
+	}
+	handler.logger(c).Debug("Inbox shown")
+	return 200, message
+}
+
+func (handler WebserviceHandler) UpdateGameValue(c *gin.Context) (int, result.Game) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Game{}
+	}
+	gameId, err := strconv.Atoi(c.Param("gameId"))
+	if err != nil {
+		c.Error(err)
+		return 400, result.Game{}
+	}
+	value := request.GameValue{}
+	err = c.BindJSON(&value)
+	if err != nil {
+		return 400, result.Game{}
+	}
+
+	game, err, code := handler.interactor(c).UpdateGameValue(c.Request.Context(), userId, gameId, *value.Value)
+	if err != nil {
+		c.Error(err)
+		return code, result.Game{}
+	}
+
+	message := result.Game{Id: game.Id, UserId: userId, Name: game.Name,
+		Producer: game.Producer, Value: game.Value, Version: game.Version}
+	handler.logger(c).Debug("Game value updated", "gameId", game.Id)
+	return 200, message
+}
@@ -0,0 +1,93 @@
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"game-tracker/usecases"
+)
+
+type DbWishlistRepo DbRepo
+
+func NewDbWishlistRepo(dbHandlers map[string]DbHandler) *DbWishlistRepo {
+	dbWishlistRepo := new(DbWishlistRepo)
+	dbWishlistRepo.dbHandlers = dbHandlers
+	dbWishlistRepo.dbHandler = dbHandlers["DbWishlistRepo"]
+	return dbWishlistRepo
+}
+
+func (repo DbWishlistRepo) Store(ctx context.Context, entry usecases.WishlistEntry) error {
+	_, err := repo.dbHandler.Execute(ctx, `INSERT INTO wishlist (user_id, game_id, target_price,
+		added_at) VALUES ($1, $2, $3, $4)`, entry.UserId, entry.GameId, entry.TargetPrice,
+		entry.AddedAt)
+	return err
+}
+
+func (repo DbWishlistRepo) Remove(ctx context.Context, userId, gameId int) error {
+	_, err := repo.dbHandler.Execute(ctx, `DELETE FROM wishlist WHERE user_id=$1 AND game_id=$2`,
+		userId, gameId)
+	return err
+}
+
+func (repo DbWishlistRepo) FindByUser(ctx context.Context, userId int) ([]usecases.WishlistEntry, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT game_id, target_price, added_at FROM wishlist
+		WHERE user_id=$1 ORDER BY added_at`, userId)
+	if err != nil {
+		return nil, err
+	}
+	var entries []usecases.WishlistEntry
+	defer row.Close()
+	for row.Next() {
+		entry := usecases.WishlistEntry{UserId: userId}
+		var targetPrice sql.NullFloat64
+		err = row.Scan(&entry.GameId, &targetPrice, &entry.AddedAt)
+		if err != nil {
+			return nil, err
+		}
+		if targetPrice.Valid {
+			entry.TargetPrice = &targetPrice.Float64
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (repo DbWishlistRepo) Contains(ctx context.Context, userId, gameId int) (bool, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id FROM wishlist
+		WHERE user_id=$1 AND game_id=$2 LIMIT 1`, userId, gameId)
+	if err != nil {
+		return false, err
+	}
+	defer row.Close()
+	return row.Next(), nil
+}
+
+func (repo DbWishlistRepo) NotifyPriceDrop(ctx context.Context, gameId int, value float64, message string, createdAt time.Time) error {
+	_, err := repo.dbHandler.Execute(ctx, `INSERT INTO notifications (user_id, game_id, message,
+		value, created_at) SELECT user_id, game_id, $2, $3, $4 FROM wishlist
+		WHERE game_id=$1 AND target_price IS NOT NULL AND target_price > $3
+		AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)`,
+		gameId, message, value, createdAt)
+	return err
+}
+
+func (repo DbWishlistRepo) FindNotifications(ctx context.Context, userId int) ([]usecases.Notification, error) {
+	row, err := repo.dbHandler.Query(ctx, `SELECT id, game_id, message, value, created_at
+		FROM notifications WHERE user_id=$1 ORDER BY created_at DESC`, userId)
+	if err != nil {
+		return nil, err
+	}
+	var notifications []usecases.Notification
+	defer row.Close()
+	for row.Next() {
+		notification := usecases.Notification{UserId: userId}
+		err = row.Scan(&notification.Id, &notification.GameId, &notification.Message,
+			&notification.Value, &notification.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
@@ -1,53 +1,261 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"game-tracker/infrastructure"
 	"game-tracker/interfaces"
+	"game-tracker/middlewares/metrics"
 	"game-tracker/models/postgres"
+	"game-tracker/models/responses"
 	"game-tracker/routes"
 	"game-tracker/usecases"
 )
 
+// Set at link time, like
+// go build -ldflags "-X main.commit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%FT%TZ)"
+var (
+	commit    = "unknown"
+	buildTime = "unknown"
+)
+
 func main() {
-	file, err := os.Open("config.json")
+	config, err := infrastructure.LoadConfiguration(os.Args[1:])
 	if err != nil {
-		fmt.Println("Cannot open config file")
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if config.BaseURL != "" {
+		responses.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	}
+	logger, err := infrastructure.NewLogger(config.LogLevel, config.LogFormat, os.Stdout)
+	if err != nil {
+		fmt.Println("Cannot set up logging", err)
 		return
 	}
-	decoder := json.NewDecoder(file)
-	config := postgres.Configuration{}
-	err = decoder.Decode(&config)
+	loggerRepo := interfaces.NewLoggerRepo(logger)
+
+	shutdownTracing, err := infrastructure.SetUpTracing(config.TraceExporter, config.TraceEndpoint)
 	if err != nil {
-		fmt.Println("Cannot read config file")
+		loggerRepo.Error("Cannot set up tracing", "error", err)
 		return
 	}
-	dbHandler, err := infrastructure.NewPostgresqlHandler(config.PostgresAdr)
+	defer shutdownTracing(context.Background())
+
+	// The pool never keeps idle more connections than it opens, whether the
+	// idle ones are set or left to the default of 2
+	idleConns := config.MaxIdleConns
+	if idleConns == 0 {
+		idleConns = 2
+	}
+	if config.MaxOpenConns != 0 && idleConns > config.MaxOpenConns {
+		idleConns = config.MaxOpenConns
+	}
+	statementCache := config.StatementCacheSize
+	if statementCache == 0 {
+		statementCache = 100
+	}
+	dbHandler, err := infrastructure.NewPostgresqlHandler(config.PostgresAdr,
+		infrastructure.PoolOptions{
+			MaxOpenConns:    config.MaxOpenConns,
+			MaxIdleConns:    idleConns,
+			ConnMaxLifetime: seconds(config.ConnMaxLifetime),
+			ConnMaxIdleTime: seconds(config.ConnMaxIdleTime),
+			StatementCache:  statementCache,
+		})
 	if err != nil {
-		fmt.Println("Cannot open database", err)
+		loggerRepo.Error("Cannot open database", "error", err)
 		return
 	}
 
+	err = infrastructure.RegisterDbStats(dbHandler, "postgres")
+	if err != nil {
+		loggerRepo.Error("Cannot expose database statistics", "error", err)
+		return
+	}
+
+	// Every repository gets its own measured and traced handler, to tell them
+	// apart
 	handlers := make(map[string]interfaces.DbHandler)
-	handlers["DbUserRepo"] = dbHandler
-	handlers["DbPlayerRepo"] = dbHandler
-	handlers["DbGameRepo"] = dbHandler
-	handlers["DbLibraryRepo"] = dbHandler
+	for _, repo := range []string{"DbUserRepo", "DbPlayerRepo", "DbGameRepo", "DbLibraryRepo",
+		"DbAchievementRepo", "DbWishlistRepo", "DbTagRepo", "DbShareRepo", "DbFriendRepo",
+		"DbEventRepo", "DbAuditRepo", "DbHealthRepo"} {
+		handlers[repo] = infrastructure.NewTracedDbHandler(
+			infrastructure.NewMeasuredDbHandler(dbHandler, repo), repo)
+	}
+
+	health := interfaces.NewDbHealthRepo(dbHandler, handlers)
+	err = waitForDatabase(health, time.Duration(config.DbStartupTimeout)*time.Second, loggerRepo)
+	if err != nil {
+		loggerRepo.Error("Database not ready", "error", err)
+		return
+	}
+	warmUpCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	err = dbHandler.WarmUp(warmUpCtx, idleConns)
+	cancel()
+	if err != nil {
+		loggerRepo.Error("Cannot open the connections of the pool", "error", err)
+		return
+	}
 
 	profileInteractor := usecases.ProfileInteractor{
-		UserRepository:    interfaces.NewDbUserRepo(handlers),
-		GameRepository:    interfaces.NewDbGameRepo(handlers),
-		LibraryRepository: interfaces.NewDbLibraryRepo(handlers),
+		UserRepository:        interfaces.NewDbUserRepo(handlers),
+		PlayerRepository:      interfaces.NewDbPlayerRepo(handlers),
+		GameRepository:        interfaces.NewDbGameRepo(handlers),
+		LibraryRepository:     interfaces.NewDbLibraryRepo(handlers),
+		AchievementRepository: interfaces.NewDbAchievementRepo(handlers),
+		WishlistRepository:    interfaces.NewDbWishlistRepo(handlers),
+		TagRepository:         interfaces.NewDbTagRepo(handlers),
+		ShareRepository:       interfaces.NewDbShareRepo(handlers),
+		FriendRepository:      interfaces.NewDbFriendRepo(handlers),
+		EventRepository:       interfaces.NewDbEventRepo(handlers),
+		AuditRepository:       interfaces.NewDbAuditRepo(handlers),
+		Loggr:                 loggerRepo,
+		Metrics:               infrastructure.ActionCounter{},
+		Tracer:                infrastructure.SpanTracer{},
+		Retention:             time.Duration(config.RetentionDays) * 24 * time.Hour,
 	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go purgeRemoved(ctx, &profileInteractor)
 
 	webserviceHandler := interfaces.WebserviceHandler{}
 	webserviceHandler.ProfileInteractor = profileInteractor
+	webserviceHandler.Health = health
+	webserviceHandler.Build = interfaces.BuildInfo{Commit: commit, BuildTime: buildTime}
+	webserviceHandler.TokenKey = config.JWTKeys[0]
+	webserviceHandler.TokenLifetime = seconds(config.TokenLifetime)
+	if webserviceHandler.TokenLifetime == 0 {
+		webserviceHandler.TokenLifetime = 24 * time.Hour
+	}
+
+	engine := routes.CreateEngine(webserviceHandler, config)
+
+	server := &http.Server{
+		Addr:         config.ListenAddress,
+		Handler:      engine,
+		ReadTimeout:  seconds(config.ReadTimeout),
+		WriteTimeout: seconds(config.WriteTimeout),
+		IdleTimeout:  seconds(config.IdleTimeout),
+	}
+	if server.Addr == "" {
+		server.Addr = ":8080"
+	}
+	metricsServer := &http.Server{
+		Addr:              config.MetricsAddress,
+		Handler:           metrics.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if metricsServer.Addr == "" {
+		metricsServer.Addr = "127.0.0.1:8081"
+	}
+	go func() {
+		err := metricsServer.ListenAndServe()
+		if err != http.ErrServerClosed {
+			loggerRepo.Error("Cannot serve metrics", "error", err)
+		}
+	}()
 
-	engine := routes.CreateEngine(webserviceHandler)
+	loggerRepo.Info("Listening", "address", server.Addr, "metricsAddress", metricsServer.Addr,
+		"tls", config.TLSCertFile != "", "commit", commit)
+	err = serve(ctx, server, config)
+	if err != nil {
+		loggerRepo.Error("Cannot serve", "error", err)
+	}
+	metricsServer.Close()
 
-	fmt.Println("Listening...")
-	engine.Run(":8080")
+	err = dbHandler.Close()
+	if err != nil {
+		loggerRepo.Error("Cannot close database", "error", err)
+	}
+	loggerRepo.Info("Stopped")
+}
+
+// serve runs the server until ctx is done, then stops taking requests and
+// lets those in flight finish within the shutdown timeout. The server speaks
+// HTTPS when a certificate and key are configured.
+func serve(ctx context.Context, server *http.Server, config postgres.Configuration) error {
+	errs := make(chan error, 1)
+	go func() {
+		if config.TLSCertFile != "" {
+			errs <- server.ListenAndServeTLS(config.TLSCertFile, config.TLSKeyFile)
+		} else {
+			errs <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	timeout := seconds(config.ShutdownTimeout)
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
+
+// waitForDatabase pings the database until it answers, waiting twice as long
+// after each failure up to 10 seconds, and gives up past the timeout. The
+// database must then be migrated as far as the repositories expect.
+func waitForDatabase(health *interfaces.DbHealthRepo, timeout time.Duration, logger usecases.LoggerRepository) error {
+	deadline := time.Now().Add(timeout)
+	wait := 500 * time.Millisecond
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := health.Ping(ctx)
+		cancel()
+		if err == nil {
+			break
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return err
+		}
+		logger.Warn("Database unreachable, retrying", "error", err, "retryIn", wait)
+		time.Sleep(wait)
+		wait *= 2
+		if wait > 10*time.Second {
+			wait = 10 * time.Second
+		}
+	}
+
+	version, err := health.SchemaVersion(context.Background())
+	if err != nil {
+		return fmt.Errorf("Cannot read schema version: %v", err)
+	}
+	if version < interfaces.SchemaVersion {
+		return fmt.Errorf("Schema at version %d, run the migrations up to %d", version,
+			interfaces.SchemaVersion)
+	}
+	return nil
+}
+
+// purgeRemoved drops the users and libraries past their retention period, at
+// startup and then every hour until ctx is done
+func purgeRemoved(ctx context.Context, interactor *usecases.ProfileInteractor) {
+	for {
+		err := interactor.PurgeRemoved(ctx)
+		if err != nil && ctx.Err() == nil {
+			interactor.Loggr.Error("Cannot purge removed users and libraries", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Hour):
+		}
+	}
 }
@@ -5,52 +5,112 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"strconv"
+	"time"
 )
 
-func CheckToken() gin.HandlerFunc {
+// CheckToken lets through valid tokens of the user in the path, signed with
+// any of keys
+func CheckToken(keys []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString := c.Request.Header.Get("X-Auth-Key")
-		if tokenString == "" {
-			err := fmt.Errorf("Token cannot be empty")
+		tokenId, err := tokenUserId(c, keys)
+		if err != nil {
 			c.AbortWithError(400, err)
 			return
 		}
 
-		token, err := jwt.Parse(tokenString,
-			func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-				}
-				return []byte("5230"), nil
-			})
+		id, err := strconv.Atoi(c.Param("id"))
 		if err != nil {
 			c.AbortWithError(400, err)
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !(ok && token.Valid) {
-			if !ok {
-				err = fmt.Errorf("Error parsing claims")
-			}
-			if !token.Valid {
-				err = fmt.Errorf("Token invalid")
-			}
+		if tokenId != id {
+			err := fmt.Errorf("Id in token and query mismatch")
 			c.AbortWithError(400, err)
 			return
 		}
+		c.Set("userId", tokenId)
+		c.Next()
+	}
+}
 
-		id, err := strconv.Atoi(c.Param("id"))
+// Authenticate lets through any valid token, for routes outside of a user.
+// The id of the token's user is set as "userId".
+func Authenticate(keys []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenId, err := tokenUserId(c, keys)
 		if err != nil {
 			c.AbortWithError(400, err)
 			return
 		}
+		c.Set("userId", tokenId)
+		c.Next()
+	}
+}
 
-		if int(claims["id"].(float64)) != id {
-			err := fmt.Errorf("Id in token and query mismatch")
+// Identify lets through requests with a valid token or none at all. The id
+// of the token's user is set as "userId", left unset for anonymous requests.
+func Identify(keys []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Header.Get("X-Auth-Key") == "" {
+			c.Next()
+			return
+		}
+		tokenId, err := tokenUserId(c, keys)
+		if err != nil {
 			c.AbortWithError(400, err)
 			return
 		}
+		c.Set("userId", tokenId)
 		c.Next()
 	}
 }
+
+// tokenUserId reads the user id of the token, trying every key in turn so
+// tokens signed with a retired key still work until they expire. Tokens
+// without an expiry are refused.
+func tokenUserId(c *gin.Context, keys []string) (int, error) {
+	tokenString := c.Request.Header.Get("X-Auth-Key")
+	if tokenString == "" {
+		return 0, fmt.Errorf("Token cannot be empty")
+	}
+
+	var token *jwt.Token
+	err := fmt.Errorf("No key to check the token with")
+	for _, key := range keys {
+		token, err = jwt.Parse(tokenString,
+			func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+				}
+				return []byte(key), nil
+			})
+		// Only a wrong signature is worth trying the next key
+		validation, ok := err.(*jwt.ValidationError)
+		if err == nil || !(ok && validation.Errors&jwt.ValidationErrorSignatureInvalid != 0) {
+			break
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !(ok && token.Valid) {
+		if !ok {
+			err = fmt.Errorf("Error parsing claims")
+		}
+		if !token.Valid {
+			err = fmt.Errorf("Token invalid")
+		}
+		return 0, err
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return 0, fmt.Errorf("Token has no expiry")
+	}
+	id, ok := claims["id"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("Token has no user id")
+	}
+	return int(id), nil
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"time"
+
+	"game-tracker/usecases"
+)
+
+// Requests gives every request a logger with its request id and route, set
+// as "logger", and logs the request once served. A request id sent in
+// X-Request-Id is kept, others get a new one.
+func Requests(logger usecases.LoggerRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestId := c.Request.Header.Get("X-Request-Id")
+		if requestId == "" {
+			requestId = newRequestId()
+		}
+		c.Header("X-Request-Id", requestId)
+		requestLogger := logger.With("requestId", requestId, "route", c.FullPath())
+		c.Set("logger", requestLogger)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		fields := []interface{}{"method", c.Request.Method, "path", c.Request.URL.Path,
+			"status", status, "latency", time.Since(start)}
+		if userId := c.GetInt("userId"); userId != 0 {
+			fields = append(fields, "userId", userId)
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, "errors", c.Errors.String())
+		}
+		switch {
+		case status >= 500:
+			requestLogger.Error("Request failed", fields...)
+		case status >= 400:
+			requestLogger.Warn("Request refused", fields...)
+		default:
+			requestLogger.Info("Request served", fields...)
+		}
+	}
+}
+
+func newRequestId() string {
+	id := make([]byte, 8)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
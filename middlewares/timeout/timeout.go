@@ -0,0 +1,40 @@
+package timeout
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// Requests cuts the context of every request short after its timeout, so the
+// statements it still runs are canceled. Routes are timed by method and route
+// template, like "GET /users/:id/export", a timeout of 0 leaving the route
+// uncut. Other routes get the default timeout.
+func Requests(defaultTimeout time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = defaultTimeout
+		}
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+			defer cancel()
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		c.Next()
+
+		// Failures past the timeout, or after the client left, are theirs
+		// whatever the use case made of them
+		code := c.GetInt("code")
+		if c.Errors.Last() == nil || code < 500 {
+			return
+		}
+		switch c.Request.Context().Err() {
+		case context.DeadlineExceeded:
+			c.Set("code", 504)
+		case context.Canceled:
+			c.Set("code", 499)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+CREATE TABLE achievements (
+	id          SERIAL PRIMARY KEY,
+	game_id     INTEGER NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+	name        TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	points      INTEGER NOT NULL DEFAULT 0,
+	hidden      BOOLEAN NOT NULL DEFAULT FALSE,
+	UNIQUE (game_id, name)
+);
+
+CREATE TABLE player_achievements (
+	id             SERIAL PRIMARY KEY,
+	player_id      INTEGER NOT NULL REFERENCES players (id) ON DELETE CASCADE,
+	achievement_id INTEGER NOT NULL REFERENCES achievements (id) ON DELETE CASCADE,
+	unlocked_at    TIMESTAMPTZ NOT NULL,
+	UNIQUE (player_id, achievement_id)
+);
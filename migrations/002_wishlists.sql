@@ -0,0 +1,17 @@
+CREATE TABLE wishlist (
+	id           SERIAL PRIMARY KEY,
+	user_id      INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	game_id      INTEGER NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+	target_price NUMERIC,
+	added_at     TIMESTAMPTZ NOT NULL,
+	UNIQUE (user_id, game_id)
+);
+
+CREATE TABLE notifications (
+	id         SERIAL PRIMARY KEY,
+	user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	game_id    INTEGER NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+	message    TEXT NOT NULL,
+	value      NUMERIC NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);
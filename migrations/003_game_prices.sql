@@ -0,0 +1,13 @@
+CREATE TABLE game_prices (
+	id          SERIAL PRIMARY KEY,
+	game_id     INTEGER NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+	value       NUMERIC NOT NULL,
+	source      TEXT NOT NULL,
+	recorded_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX game_prices_game_id_recorded_at ON game_prices (game_id, recorded_at);
+
+-- Existing catalog values become the first entry of each history
+INSERT INTO game_prices (game_id, value, source, recorded_at)
+	SELECT id, value, 'catalog', now() FROM games;
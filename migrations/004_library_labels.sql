@@ -0,0 +1,13 @@
+ALTER TABLE libraries
+	ADD COLUMN name TEXT NOT NULL DEFAULT '',
+	ADD COLUMN description TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE game_tags (
+	id         SERIAL PRIMARY KEY,
+	library_id INTEGER NOT NULL REFERENCES libraries (id) ON DELETE CASCADE,
+	game_id    INTEGER NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+	tag        TEXT NOT NULL,
+	UNIQUE (library_id, game_id, tag)
+);
+
+CREATE INDEX game_tags_tag ON game_tags (tag);
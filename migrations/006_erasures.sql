@@ -0,0 +1,10 @@
+-- Tombstones of erased users. Names are only kept hashed, enough to answer
+-- "was this account erased" without holding the personal data itself.
+CREATE TABLE erasures (
+	id             SERIAL PRIMARY KEY,
+	user_id        INTEGER NOT NULL,
+	player_id      INTEGER NOT NULL,
+	user_name_hash TEXT NOT NULL,
+	player_erased  BOOLEAN NOT NULL,
+	erased_at      TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,6 @@
+-- Removed users and libraries are only marked, and purged for good once
+-- their retention period has passed.
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE libraries ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX libraries_deleted_at ON libraries (deleted_at) WHERE deleted_at IS NOT NULL;
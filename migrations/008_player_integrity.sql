@@ -0,0 +1,28 @@
+-- Player names are unique and every user points at an existing player.
+-- Players sharing a name are merged into the oldest one first, and players
+-- missing behind users are recreated with a placeholder name.
+CREATE TEMPORARY TABLE duplicate_players AS
+	SELECT players.id, kept.id AS kept_id FROM players
+	JOIN (SELECT player_name, MIN(id) AS id FROM players GROUP BY player_name) AS kept
+	ON kept.player_name = players.player_name AND kept.id <> players.id;
+
+UPDATE users SET player_id = duplicate_players.kept_id FROM duplicate_players
+	WHERE users.player_id = duplicate_players.id;
+INSERT INTO player_achievements (player_id, achievement_id, unlocked_at)
+	SELECT duplicate_players.kept_id, player_achievements.achievement_id,
+		MIN(player_achievements.unlocked_at)
+	FROM player_achievements
+	JOIN duplicate_players ON duplicate_players.id = player_achievements.player_id
+	GROUP BY duplicate_players.kept_id, player_achievements.achievement_id
+	ON CONFLICT (player_id, achievement_id) DO NOTHING;
+DELETE FROM players WHERE id IN (SELECT id FROM duplicate_players);
+DROP TABLE duplicate_players;
+
+INSERT INTO players (id, player_name)
+	SELECT DISTINCT player_id, 'player-' || player_id FROM users
+	WHERE player_id NOT IN (SELECT id FROM players);
+SELECT setval(pg_get_serial_sequence('players', 'id'), (SELECT MAX(id) FROM players));
+
+ALTER TABLE players ADD CONSTRAINT players_player_name_key UNIQUE (player_name);
+ALTER TABLE users ADD CONSTRAINT users_player_id_fkey
+	FOREIGN KEY (player_id) REFERENCES players (id);
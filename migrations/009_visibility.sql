@@ -0,0 +1,14 @@
+-- Profiles stay public and libraries private, as they were before
+ALTER TABLE users ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public'
+	CHECK (visibility IN ('private', 'friends', 'public'));
+ALTER TABLE libraries ADD COLUMN visibility TEXT NOT NULL DEFAULT 'private'
+	CHECK (visibility IN ('private', 'friends', 'public'));
+
+CREATE TABLE library_shares (
+	id         SERIAL PRIMARY KEY,
+	library_id INTEGER NOT NULL REFERENCES libraries (id) ON DELETE CASCADE,
+	token      TEXT NOT NULL UNIQUE,
+	created_at TIMESTAMPTZ NOT NULL,
+	revoked_at TIMESTAMPTZ
+);
+CREATE INDEX library_shares_library_id ON library_shares (library_id);
@@ -0,0 +1,23 @@
+-- A friendship starts as a request from user_id to friend_id. Two users have
+-- at most one, whoever asked first.
+CREATE TABLE friendships (
+	user_id      INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	friend_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	status       TEXT NOT NULL CHECK (status IN ('pending', 'accepted')),
+	requested_at TIMESTAMPTZ NOT NULL,
+	accepted_at  TIMESTAMPTZ,
+	PRIMARY KEY (user_id, friend_id),
+	CHECK (user_id <> friend_id)
+);
+CREATE UNIQUE INDEX friendships_pair
+	ON friendships (LEAST(user_id, friend_id), GREATEST(user_id, friend_id));
+CREATE INDEX friendships_friend_id ON friendships (friend_id);
+
+CREATE TABLE follows (
+	follower_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	followee_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	followed_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (follower_id, followee_id),
+	CHECK (follower_id <> followee_id)
+);
+CREATE INDEX follows_followee_id ON follows (followee_id);
@@ -0,0 +1,11 @@
+-- What happened in the libraries of a user, newest first in feeds. Ids grow
+-- with time and page the feeds.
+CREATE TABLE events (
+	id         SERIAL PRIMARY KEY,
+	kind       TEXT NOT NULL,
+	user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	library_id INTEGER NOT NULL REFERENCES libraries (id) ON DELETE CASCADE,
+	game_id    INTEGER NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX events_user_id ON events (user_id, id DESC);
@@ -0,0 +1,28 @@
+-- Admins read the audit log. There is no endpoint making admins, grant it
+-- with UPDATE users SET is_admin = true WHERE id = ...
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+
+-- Actors and targets are plain ids, entries outlive the users they are about
+CREATE TABLE audit_log (
+	id          SERIAL PRIMARY KEY,
+	action      TEXT NOT NULL,
+	actor_id    INTEGER NOT NULL,
+	target_type TEXT NOT NULL,
+	target_id   INTEGER NOT NULL,
+	detail      TEXT NOT NULL,
+	ip          TEXT NOT NULL,
+	created_at  TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX audit_log_actor_id ON audit_log (actor_id, created_at);
+CREATE INDEX audit_log_action ON audit_log (action, created_at);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_log is append-only';
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+	FOR EACH ROW EXECUTE PROCEDURE audit_log_append_only();
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+	FOR EACH STATEMENT EXECUTE PROCEDURE audit_log_append_only();
@@ -0,0 +1,6 @@
+-- The last migration applied, read by /readyz and at startup. Every migration
+-- from now on ends by setting it to its own number.
+CREATE TABLE schema_version (
+	version INTEGER NOT NULL
+);
+INSERT INTO schema_version (version) VALUES (13);
@@ -0,0 +1,9 @@
+-- User names are unique, as renames and logins expect. Of users sharing a
+-- name from before, the oldest keeps it and the others get their id appended;
+-- their logins still carry the old name and must be renamed to match.
+UPDATE users SET user_name = user_name || '-' || id
+	WHERE id NOT IN (SELECT MIN(id) FROM users GROUP BY user_name);
+
+ALTER TABLE users ADD CONSTRAINT users_user_name_key UNIQUE (user_name);
+
+UPDATE schema_version SET version = 14;
@@ -1,5 +1,65 @@
 package postgres
 
 type Configuration struct {
-	PostgresAdr string
+	ListenAddress string //host:port to serve on, :8080 when unset
+	// host:port serving /metrics, apart from the API so that only those who
+	// can reach it scrape the metrics. 127.0.0.1:8081 when unset.
+	MetricsAddress string
+	// Address the links of responses start with, http://localhost:8080 when
+	// unset
+	BaseURL string
+	// Origins browsers may call the API from, * for any. No cross-origin
+	// requests when unset.
+	CORSOrigins []string
+	// Addresses or CIDR ranges of the proxies in front of the server, whose
+	// X-Forwarded-For gives the client address in the audit log. Unset trusts
+	// no proxy, the client is the peer of the connection.
+	TrustedProxies []string
+	// Seconds the server gives to read a request, to write a response and to
+	// keep an idle connection. Unset waits forever. Writes must outlast the
+	// longest route timeout.
+	ReadTimeout  int
+	WriteTimeout int
+	IdleTimeout  int
+	// Seconds requests in flight get to finish on SIGTERM, 30 when unset
+	ShutdownTimeout int
+	// Served over HTTPS when both are set
+	TLSCertFile string
+	TLSKeyFile  string
+	// Seconds a request can run before its statements are canceled, 30 when
+	// unset
+	RequestTimeout int
+	// Seconds by method and route template, like "GET /users/:id/export",
+	// overriding RequestTimeout. 0 lets the route run as long as it takes.
+	RouteTimeouts map[string]int
+
+	PostgresAdr     string
+	PostgresAdrFile string //File holding PostgresAdr, to keep the password out of here
+	// Connections the pool opens at most and keeps idle, unlimited and 2
+	// when unset, idle ones never above MaxOpenConns
+	MaxOpenConns int
+	MaxIdleConns int
+	// Seconds a connection is used at most and kept idle at most, forever
+	// when unset
+	ConnMaxLifetime int
+	ConnMaxIdleTime int
+	// Statements kept prepared by the pool, 100 when unset and none when -1,
+	// as behind a transaction pooling proxy
+	StatementCacheSize int
+	// Seconds startup waits for the database, retrying with backoff. 0 gives
+	// up at the first failure.
+	DbStartupTimeout int
+
+	// Keys of the login tokens, of 16 characters at least. The first signs new
+	// tokens, the others are still accepted for the tokens they signed, which
+	// all expired once TokenLifetime has passed since the key was replaced.
+	JWTKeys       []string
+	JWTKeysFile   string //File holding JWTKeys, one per line
+	TokenLifetime int    //Seconds a login token is valid, a day when unset
+
+	RetentionDays int    //Days removed users and libraries can be restored, 30 when unset
+	LogLevel      string //debug, info, warn or error, info when unset
+	LogFormat     string //json or console, console when unset
+	TraceExporter string //stdout, otlp or none, none when unset
+	TraceEndpoint string //host:port of the OTLP collector, localhost:4318 when unset
 }
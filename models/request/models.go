@@ -16,8 +16,50 @@ type Game struct {
 }
 
 type User struct {
-	PlayerId   int    `json:"playerId" binding:"required"`
+	PlayerId   int    `json:"playerId"` //0 to find the player by name
 	PlayerName string `json:"playerName" binding:"required"`
 	Name       string `json:"name" binding:"required"`
 	Password   string `json:"password" binding:"required"`
 }
+
+type Achievement struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Points      int    `json:"points"`
+	Hidden      bool   `json:"hidden"`
+}
+
+type WishlistEntry struct {
+	TargetPrice *float64 `json:"targetPrice"`
+}
+
+type GameValue struct {
+	Value *float64 `json:"value" binding:"required"`
+}
+
+type Library struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
+}
+
+type Tag struct {
+	Tag string `json:"tag" binding:"required"`
+}
+
+type Transfer struct {
+	TargetLibraryId int `json:"targetLibraryId" binding:"required"`
+}
+
+type BatchTransfer struct {
+	TargetLibraryId int   `json:"targetLibraryId" binding:"required"`
+	GameIds         []int `json:"gameIds" binding:"required"`
+}
+
+type Player struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type Friend struct {
+	UserId int `json:"userId" binding:"required"`
+}
@@ -4,9 +4,18 @@ import (
 	"fmt"
 )
 
+// BaseURL starts the links of every response, set from the configuration
+var BaseURL = "http://localhost:8080"
+
+// link is a link to the path given by format and args
+func link(format string, args ...interface{}) string {
+	return BaseURL + fmt.Sprintf(format, args...)
+}
+
 type Links struct {
 	Self    string `json:"self,omitempty"`
 	Related string `json:"related,omitempty"`
+	Next    string `json:"next,omitempty"`
 }
 
 type Data struct {
@@ -17,11 +26,43 @@ type Data struct {
 }
 
 type Attributes struct {
-	TokenString string  `json:"tokenString,omitempty"`
-	Name        string  `json:"name,omitempty"`
-	Content     string  `json:"content,omitempty"`
-	Producer    string  `json:"producer,omitempty"`
-	Value       float64 `json:"value,omitempty"`
+	TokenString string   `json:"tokenString,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Content     string   `json:"content,omitempty"`
+	Producer    string   `json:"producer,omitempty"`
+	Value       float64  `json:"value,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Points      int      `json:"points,omitempty"`
+	Hidden      bool     `json:"hidden,omitempty"`
+	Unlocked    bool     `json:"unlocked,omitempty"`
+	UnlockedAt  string   `json:"unlockedAt,omitempty"`
+	Score       int      `json:"score,omitempty"`
+	TargetPrice *float64 `json:"targetPrice,omitempty"`
+	AddedAt     string   `json:"addedAt,omitempty"`
+	CreatedAt   string   `json:"createdAt,omitempty"`
+	LowestValue float64  `json:"lowestValue,omitempty"`
+	Source      string   `json:"source,omitempty"`
+	RecordedAt  string   `json:"recordedAt,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Version     int      `json:"version,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Line        int      `json:"line,omitempty"`
+	Reason      string   `json:"reason,omitempty"`
+	AccountIds  []int    `json:"accountIds,omitempty"`
+	LibraryIds  []int    `json:"libraryIds,omitempty"`
+	Visibility  string   `json:"visibility,omitempty"`
+	Token       string   `json:"token,omitempty"`
+	RevokedAt   string   `json:"revokedAt,omitempty"`
+	AcceptedAt  string   `json:"acceptedAt,omitempty"`
+	Kind        string   `json:"kind,omitempty"`
+	Action      string   `json:"action,omitempty"`
+	ActorId     int      `json:"actorId,omitempty"`
+	TargetType  string   `json:"targetType,omitempty"`
+	TargetId    int      `json:"targetId,omitempty"`
+	Ip          string   `json:"ip,omitempty"`
+	Commit      string   `json:"commit,omitempty"`
+	BuildTime   string   `json:"buildTime,omitempty"`
+	Schema      int      `json:"schemaVersion,omitempty"`
 }
 
 type Relationships struct {
@@ -29,6 +70,9 @@ type Relationships struct {
 	Games     []Game    `json:"games,omitempty"`
 	Owner     Owner     `json:"owner,omitempty"`
 	Library   LibOfGame `json:"library,omitempty"`
+	Game      GameRef   `json:"game,omitempty"`
+	Player    Owner     `json:"player,omitempty"`
+	Users     []User    `json:"users,omitempty"`
 }
 
 type DataLv2 struct {
@@ -42,6 +86,11 @@ type Token struct {
 	Data  `json:"data, omitempty"`
 }
 
+type Health struct {
+	Links `json:"links,omitempty"`
+	Data  `json:"data,omitempty"`
+}
+
 type User struct {
 	Links `json:"links,omitempty"`
 	Data  `json:"data, omitempty"`
@@ -65,6 +114,67 @@ type Game struct {
 	Data  `json:"data, omitempty"`
 }
 
+type GameRef struct {
+	DataLv2
+}
+
+type Meta struct {
+	Completion float64 `json:"completion"`
+}
+
+type Achievement struct {
+	Links `json:"links,omitempty"`
+	Data  `json:"data,omitempty"`
+}
+
+type Achievements struct {
+	Links `json:"links,omitempty"`
+	Meta  `json:"meta,omitempty"`
+	Data  []Data `json:"data"`
+}
+
+type PriceMeta struct {
+	Value       float64 `json:"value"`
+	LowestValue float64 `json:"lowestValue"`
+}
+
+type Prices struct {
+	Links     `json:"links,omitempty"`
+	PriceMeta `json:"meta"`
+	Data      []Data `json:"data"`
+}
+
+type ImportMeta struct {
+	DryRun bool           `json:"dryRun"`
+	Counts map[string]int `json:"counts"`
+}
+
+type Import struct {
+	Links      `json:"links,omitempty"`
+	ImportMeta `json:"meta"`
+	Data       []Data `json:"data"`
+}
+
+type List struct {
+	Links `json:"links,omitempty"`
+	Data  []Data `json:"data"`
+}
+
+type Score struct {
+	Links `json:"links,omitempty"`
+	Data  `json:"data,omitempty"`
+}
+
+type Player struct {
+	Links `json:"links,omitempty"`
+	Data  `json:"data,omitempty"`
+}
+
+type Share struct {
+	Links `json:"links,omitempty"`
+	Data  `json:"data,omitempty"`
+}
+
 type Info struct {
 	Links `json:"links,omitempty"`
 	Data  `json:"data, omitempty"`
@@ -81,16 +191,18 @@ func ViewToken(tokenString string) Token {
 	}
 }
 
-func ViewUser(id int, name string, libraries []Library) User {
+func ViewUser(id int, name, visibility string, version int, libraries []Library) User {
 	return User{
 		Links: Links{
-			Self: fmt.Sprintf("http://localhost:8080/users/%d", id),
+			Self: link("/users/%d", id),
 		},
 		Data: Data{
 			Type: "users",
 			Id:   id,
 			Attributes: Attributes{
-				Name: name,
+				Name:       name,
+				Visibility: visibility,
+				Version:    version,
 			},
 			Relationships: Relationships{
 				Libraries: libraries,
@@ -102,8 +214,8 @@ func ViewUser(id int, name string, libraries []Library) User {
 func ViewInfo(info string, userId int) Info {
 	return Info{
 		Links: Links{
-			Self:    fmt.Sprintf("http://localhost:8080/users/%d/info", userId),
-			Related: fmt.Sprintf("http://localhost:8080/users/%d", userId),
+			Self:    link("/users/%d/info", userId),
+			Related: link("/users/%d", userId),
 		},
 		Data: Data{
 			Type: "info",
@@ -123,15 +235,21 @@ func ViewInfo(info string, userId int) Info {
 	}
 }
 
-func ViewLibrary(userId, libId int, games []Game) Library {
+func ViewLibrary(userId, libId int, name, description, visibility string, version int, games []Game) Library {
 	return Library{
 		Links: Links{
-			Self:    fmt.Sprintf("http://localhost:8080/users/%d/libraries/%d", userId, libId),
-			Related: fmt.Sprintf("http://localhost:8080/users/%d", userId),
+			Self:    link("/users/%d/libraries/%d", userId, libId),
+			Related: link("/users/%d", userId),
 		},
 		Data: Data{
 			Type: "libraries",
 			Id:   libId,
+			Attributes: Attributes{
+				Name:        name,
+				Description: description,
+				Visibility:  visibility,
+				Version:     version,
+			},
 			Relationships: Relationships{
 				Games: games,
 				Owner: Owner{
@@ -145,21 +263,25 @@ func ViewLibrary(userId, libId int, games []Game) Library {
 	}
 }
 
-func ViewGame(userId, libId, gameId int, name, producer string, value float64) Game {
+func ViewGame(userId, libId, gameId int, name, producer string, value, lowestValue float64,
+	tags []string, version int) Game {
 	return Game{
 		Links: Links{
-			Self: fmt.Sprintf("http://localhost:8080/users/%d/libraries/%d/games/%d",
+			Self: link("/users/%d/libraries/%d/games/%d",
 				userId, libId, gameId),
-			Related: fmt.Sprintf("http://localhost:8080/users/%d/libraries/%d/",
+			Related: link("/users/%d/libraries/%d/",
 				userId, libId),
 		},
 		Data: Data{
 			Type: "games",
 			Id:   gameId,
 			Attributes: Attributes{
-				Name:     name,
-				Producer: producer,
-				Value:    value,
+				Name:        name,
+				Producer:    producer,
+				Value:       value,
+				LowestValue: lowestValue,
+				Tags:        tags,
+				Version:     version,
 			},
 			Relationships: Relationships{
 				Library: LibOfGame{
@@ -198,3 +320,542 @@ func ViewGames(gameIds []int) []Game {
 	}
 	return games
 }
+
+func ViewAchievement(userId, gameId, achId int, name, description string, points int,
+	hidden, unlocked bool, unlockedAt string) Achievement {
+	return Achievement{
+		Links: Links{
+			Related: link("/users/%d/games/%d/achievements",
+				userId, gameId),
+		},
+		Data: ViewAchievementData(gameId, achId, name, description, points, hidden,
+			unlocked, unlockedAt),
+	}
+}
+
+func ViewAchievements(userId, gameId int, completion float64, achievements []Data) Achievements {
+	return Achievements{
+		Links: Links{
+			Self: link("/users/%d/games/%d/achievements",
+				userId, gameId),
+		},
+		Meta: Meta{
+			Completion: completion,
+		},
+		Data: achievements,
+	}
+}
+
+func ViewAchievementData(gameId, achId int, name, description string, points int,
+	hidden, unlocked bool, unlockedAt string) Data {
+	return Data{
+		Type: "achievements",
+		Id:   achId,
+		Attributes: Attributes{
+			Name:        name,
+			Description: description,
+			Points:      points,
+			Hidden:      hidden,
+			Unlocked:    unlocked,
+			UnlockedAt:  unlockedAt,
+		},
+		Relationships: Relationships{
+			Game: GameRef{
+				DataLv2: DataLv2{
+					Type: "games",
+					Id:   gameId,
+				},
+			},
+		},
+	}
+}
+
+func ViewScore(userId, playerId, score int) Score {
+	return Score{
+		Links: Links{
+			Self:    link("/users/%d/score", userId),
+			Related: link("/users/%d", userId),
+		},
+		Data: Data{
+			Type: "scores",
+			Id:   playerId,
+			Attributes: Attributes{
+				Score: score,
+			},
+			Relationships: Relationships{
+				Player: Owner{
+					DataLv2: DataLv2{
+						Type: "players",
+						Id:   playerId,
+					},
+				},
+			},
+		},
+	}
+}
+
+func ViewWishlist(userId int, entries []Data) List {
+	return List{
+		Links: Links{
+			Self:    link("/users/%d/wishlist", userId),
+			Related: link("/users/%d", userId),
+		},
+		Data: entries,
+	}
+}
+
+func ViewWishlistEntry(gameId int, targetPrice *float64, addedAt string) Data {
+	return Data{
+		Type: "wishlist",
+		Id:   gameId,
+		Attributes: Attributes{
+			TargetPrice: targetPrice,
+			AddedAt:     addedAt,
+		},
+		Relationships: Relationships{
+			Game: GameRef{
+				DataLv2: DataLv2{
+					Type: "games",
+					Id:   gameId,
+				},
+			},
+		},
+	}
+}
+
+func ViewInbox(userId int, notifications []Data) List {
+	return List{
+		Links: Links{
+			Self:    link("/users/%d/inbox", userId),
+			Related: link("/users/%d", userId),
+		},
+		Data: notifications,
+	}
+}
+
+func ViewNotification(id, gameId int, message string, value float64, createdAt string) Data {
+	return Data{
+		Type: "notifications",
+		Id:   id,
+		Attributes: Attributes{
+			Content:   message,
+			Value:     value,
+			CreatedAt: createdAt,
+		},
+		Relationships: Relationships{
+			Game: GameRef{
+				DataLv2: DataLv2{
+					Type: "games",
+					Id:   gameId,
+				},
+			},
+		},
+	}
+}
+
+func ViewCatalogGame(gameId int, name, producer string, value float64, version int) Game {
+	return Game{
+		Links: Links{
+			Related: link("/games/%d/prices", gameId),
+		},
+		Data: Data{
+			Type: "games",
+			Id:   gameId,
+			Attributes: Attributes{
+				Name:     name,
+				Producer: producer,
+				Value:    value,
+				Version:  version,
+			},
+		},
+	}
+}
+
+func ViewPrices(gameId int, value, lowestValue float64, prices []Data) Prices {
+	return Prices{
+		Links: Links{
+			Self: link("/games/%d/prices", gameId),
+		},
+		PriceMeta: PriceMeta{
+			Value:       value,
+			LowestValue: lowestValue,
+		},
+		Data: prices,
+	}
+}
+
+func ViewPrice(gameId int, value float64, source, recordedAt string) Data {
+	return Data{
+		Type: "prices",
+		Attributes: Attributes{
+			Value:      value,
+			Source:     source,
+			RecordedAt: recordedAt,
+		},
+		Relationships: Relationships{
+			Game: GameRef{
+				DataLv2: DataLv2{
+					Type: "games",
+					Id:   gameId,
+				},
+			},
+		},
+	}
+}
+
+func ViewUserGames(userId int, games []Game) List {
+	var data []Data
+	for _, game := range games {
+		data = append(data, game.Data)
+	}
+	return List{
+		Links: Links{
+			Self:    link("/users/%d/games", userId),
+			Related: link("/users/%d", userId),
+		},
+		Data: data,
+	}
+}
+
+func ViewTags(userId int, tags []string) List {
+	var data []Data
+	for _, tag := range tags {
+		data = append(data, Data{
+			Type: "tags",
+			Attributes: Attributes{
+				Name: tag,
+			},
+		})
+	}
+	return List{
+		Links: Links{
+			Self:    link("/users/%d/tags", userId),
+			Related: link("/users/%d", userId),
+		},
+		Data: data,
+	}
+}
+
+func ViewTransfers(userId, libId, targetLibId int, transfers []Data) List {
+	return List{
+		Links: Links{
+			Self:    link("/users/%d/libraries/%d", userId, libId),
+			Related: link("/users/%d/libraries/%d", userId, targetLibId),
+		},
+		Data: transfers,
+	}
+}
+
+func ViewTransfer(targetLibId, gameId int, status string) Data {
+	return Data{
+		Type: "transfers",
+		Id:   gameId,
+		Attributes: Attributes{
+			Status: status,
+		},
+		Relationships: Relationships{
+			Game: GameRef{
+				DataLv2: DataLv2{
+					Type: "games",
+					Id:   gameId,
+				},
+			},
+			Library: LibOfGame{
+				DataLv2: DataLv2{
+					Type: "libraries",
+					Id:   targetLibId,
+				},
+			},
+		},
+	}
+}
+
+func ViewImport(userId, libId int, dryRun bool, counts map[string]int, rows []Data) Import {
+	return Import{
+		Links: Links{
+			Related: link("/users/%d/libraries/%d", userId, libId),
+		},
+		ImportMeta: ImportMeta{
+			DryRun: dryRun,
+			Counts: counts,
+		},
+		Data: rows,
+	}
+}
+
+func ViewImportRow(line, gameId int, name, status, reason string) Data {
+	row := Data{
+		Type: "imports",
+		Attributes: Attributes{
+			Line:   line,
+			Name:   name,
+			Status: status,
+			Reason: reason,
+		},
+	}
+	if gameId != 0 {
+		row.Relationships.Game = GameRef{
+			DataLv2: DataLv2{
+				Type: "games",
+				Id:   gameId,
+			},
+		}
+	}
+	return row
+}
+
+func ViewPlayer(playerId int, name string, users []User) Player {
+	return Player{
+		Links: Links{
+			Self:    link("/players/%d", playerId),
+			Related: link("/players/%d/users", playerId),
+		},
+		Data: Data{
+			Type: "players",
+			Id:   playerId,
+			Attributes: Attributes{
+				Name: name,
+			},
+			Relationships: Relationships{
+				Users: users,
+			},
+		},
+	}
+}
+
+func ViewPlayerUsers(playerId int, users []User) List {
+	data := []Data{}
+	for _, user := range users {
+		data = append(data, user.Data)
+	}
+	return List{
+		Links: Links{
+			Self:    link("/players/%d/users", playerId),
+			Related: link("/players/%d", playerId),
+		},
+		Data: data,
+	}
+}
+
+func ViewAccountGames(userId int, games []Data) List {
+	return List{
+		Links: Links{
+			Self:    link("/users/%d/accounts/games", userId),
+			Related: link("/users/%d/accounts", userId),
+		},
+		Data: games,
+	}
+}
+
+func ViewAccountGame(gameId int, name, producer string, value float64, accountIds, libraryIds []int) Data {
+	return Data{
+		Type: "games",
+		Id:   gameId,
+		Attributes: Attributes{
+			Name:       name,
+			Producer:   producer,
+			Value:      value,
+			AccountIds: accountIds,
+			LibraryIds: libraryIds,
+		},
+	}
+}
+
+func ViewShares(userId, libId int, shares []Data) List {
+	return List{
+		Links: Links{
+			Self:    link("/users/%d/libraries/%d/shares", userId, libId),
+			Related: link("/users/%d/libraries/%d", userId, libId),
+		},
+		Data: shares,
+	}
+}
+
+func ViewShare(userId, libId, shareId int, token, createdAt, revokedAt string) Share {
+	return Share{
+		Links: Links{
+			Self: link("/users/%d/libraries/%d/shares/%d", userId,
+				libId, shareId),
+			Related: link("/shared/%s", token),
+		},
+		Data: Data{
+			Type: "shares",
+			Id:   shareId,
+			Attributes: Attributes{
+				Token:     token,
+				CreatedAt: createdAt,
+				RevokedAt: revokedAt,
+			},
+			Relationships: Relationships{
+				Library: LibOfGame{
+					DataLv2: DataLv2{
+						Type: "libraries",
+						Id:   libId,
+					},
+				},
+			},
+		},
+	}
+}
+
+func ViewFriends(userId int, friends []Data) List {
+	return List{
+		Links: Links{
+			Self:    link("/users/%d/friends", userId),
+			Related: link("/users/%d", userId),
+		},
+		Data: friends,
+	}
+}
+
+func ViewFriend(friendId int, status, requestedAt, acceptedAt string) Data {
+	return Data{
+		Type: "users",
+		Id:   friendId,
+		Attributes: Attributes{
+			Status:     status,
+			CreatedAt:  requestedAt,
+			AcceptedAt: acceptedAt,
+		},
+	}
+}
+
+func ViewFollows(userId int, followers bool, follows []Data) List {
+	path := "following"
+	if followers {
+		path = "followers"
+	}
+	return List{
+		Links: Links{
+			Self:    link("/users/%d/%s", userId, path),
+			Related: link("/users/%d", userId),
+		},
+		Data: follows,
+	}
+}
+
+func ViewFollow(userId int, followedAt string) Data {
+	return Data{
+		Type: "users",
+		Id:   userId,
+		Attributes: Attributes{
+			CreatedAt: followedAt,
+		},
+	}
+}
+
+func ViewGameFriends(gameId int, friends []Data) List {
+	return List{
+		Links: Links{
+			Self:    link("/games/%d/friends", gameId),
+			Related: link("/games/%d/prices", gameId),
+		},
+		Data: friends,
+	}
+}
+
+// ViewFeed links a page of events of the feed at path to the next page, if
+// there is one
+func ViewFeed(path string, limit, next int, events []Data) List {
+	links := Links{
+		Self: link("%s", path),
+	}
+	if next != 0 {
+		links.Next = link("%s?cursor=%d", path, next)
+		if limit != 0 {
+			links.Next += fmt.Sprintf("&limit=%d", limit)
+		}
+	}
+	return List{
+		Links: links,
+		Data:  events,
+	}
+}
+
+func ViewEvent(id int, kind string, userId, libId, gameId int, gameName, createdAt string) Data {
+	return Data{
+		Type: "events",
+		Id:   id,
+		Attributes: Attributes{
+			Kind:      kind,
+			Name:      gameName,
+			CreatedAt: createdAt,
+		},
+		Relationships: Relationships{
+			Owner: Owner{
+				DataLv2: DataLv2{
+					Type: "users",
+					Id:   userId,
+				},
+			},
+			Library: LibOfGame{
+				DataLv2: DataLv2{
+					Type: "libraries",
+					Id:   libId,
+				},
+			},
+			Game: GameRef{
+				DataLv2: DataLv2{
+					Type: "games",
+					Id:   gameId,
+				},
+			},
+		},
+	}
+}
+
+func ViewAuditLog(entries []Data) List {
+	return List{
+		Links: Links{
+			Self: BaseURL + "/audit",
+		},
+		Data: entries,
+	}
+}
+
+func ViewAuditEntry(id int, action string, actorId int, targetType string, targetId int, detail, ip, createdAt string) Data {
+	return Data{
+		Type: "audit",
+		Id:   id,
+		Attributes: Attributes{
+			Action:     action,
+			ActorId:    actorId,
+			TargetType: targetType,
+			TargetId:   targetId,
+			Content:    detail,
+			Ip:         ip,
+			CreatedAt:  createdAt,
+		},
+	}
+}
+
+func ViewHealth(path, status string, schemaVersion int) Health {
+	return Health{
+		Links: Links{
+			Self: BaseURL + path,
+		},
+		Data: Data{
+			Type: "health",
+			Attributes: Attributes{
+				Status: status,
+				Schema: schemaVersion,
+			},
+		},
+	}
+}
+
+func ViewVersion(commit, buildTime string, schemaVersion int) Health {
+	return Health{
+		Links: Links{
+			Self: BaseURL + "/version",
+		},
+		Data: Data{
+			Type: "version",
+			Attributes: Attributes{
+				Commit:    commit,
+				BuildTime: buildTime,
+				Schema:    schemaVersion,
+			},
+		},
+	}
+}
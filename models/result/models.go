@@ -4,11 +4,14 @@ type User struct {
 	Id         int    `json:"UserId"`
 	Name       string `json:"name"`
 	LibraryIds []int  `json:"libraryIds"`
+	Visibility string `json:"visibility"`
+	Version    int    `json:"version"`
 }
 
 type UserAdd struct {
-	Id   int    `json:"userId"`
-	Name string `json:"name"`
+	Id       int    `json:"userId"`
+	Name     string `json:"name"`
+	PlayerId int    `json:"playerId"`
 }
 
 type UserDelete struct {
@@ -21,12 +24,15 @@ type UserInfo struct {
 }
 
 type Game struct {
-	Id        int     `json:"gameId"`
-	LibraryId int     `json:"libraryId"`
-	UserId    int     `json:"userId"`
-	Name      string  `json:"name"`
-	Producer  string  `json:"producer"`
-	Value     float64 `json:"value"`
+	Id          int      `json:"gameId"`
+	LibraryId   int      `json:"libraryId"`
+	UserId      int      `json:"userId"`
+	Name        string   `json:"name"`
+	Producer    string   `json:"producer"`
+	Value       float64  `json:"value"`
+	LowestValue float64  `json:"lowestValue"`
+	Tags        []string `json:"tags"`
+	Version     int      `json:"version"`
 }
 
 type GameToLib struct {
@@ -36,16 +42,250 @@ type GameToLib struct {
 }
 
 type Library struct {
-	Id       int   `json:"libraryId"`
-	UserId   int   `json:"userId"`
-	GamesIds []int `json:"gameIds"`
+	Id          int    `json:"libraryId"`
+	UserId      int    `json:"userId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	GamesIds    []int  `json:"gameIds"`
+	Visibility  string `json:"visibility"`
+	Version     int    `json:"version"`
 }
 
 type LibraryAdd struct {
-	Id     int `json:"libraryId"`
-	UserId int `json:"userId"`
+	Id          int    `json:"libraryId"`
+	UserId      int    `json:"userId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 type LibraryDelete struct {
 	Id int `json:"libraryId"`
 }
+
+type Achievement struct {
+	Id          int    `json:"achievementId"`
+	GameId      int    `json:"gameId"`
+	UserId      int    `json:"userId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Points      int    `json:"points"`
+	Hidden      bool   `json:"hidden"`
+	Unlocked    bool   `json:"unlocked"`
+	UnlockedAt  string `json:"unlockedAt"`
+}
+
+type Achievements struct {
+	GameId       int           `json:"gameId"`
+	UserId       int           `json:"userId"`
+	Completion   float64       `json:"completion"`
+	Achievements []Achievement `json:"achievements"`
+}
+
+type GamerScore struct {
+	UserId   int `json:"userId"`
+	PlayerId int `json:"playerId"`
+	Score    int `json:"score"`
+}
+
+type WishlistEntry struct {
+	GameId      int      `json:"gameId"`
+	UserId      int      `json:"userId"`
+	TargetPrice *float64 `json:"targetPrice"`
+	AddedAt     string   `json:"addedAt"`
+}
+
+type Wishlist struct {
+	UserId  int             `json:"userId"`
+	Entries []WishlistEntry `json:"entries"`
+}
+
+type Notification struct {
+	Id        int     `json:"notificationId"`
+	UserId    int     `json:"userId"`
+	GameId    int     `json:"gameId"`
+	Message   string  `json:"message"`
+	Value     float64 `json:"value"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+type Inbox struct {
+	UserId        int            `json:"userId"`
+	Notifications []Notification `json:"notifications"`
+}
+
+type Price struct {
+	Value      float64 `json:"value"`
+	Source     string  `json:"source"`
+	RecordedAt string  `json:"recordedAt"`
+}
+
+type Prices struct {
+	GameId      int     `json:"gameId"`
+	Value       float64 `json:"value"`
+	LowestValue float64 `json:"lowestValue"`
+	Prices      []Price `json:"prices"`
+}
+
+type Tags struct {
+	UserId    int      `json:"userId"`
+	LibraryId int      `json:"libraryId"`
+	GameId    int      `json:"gameId"`
+	Tags      []string `json:"tags"`
+}
+
+type UserGames struct {
+	UserId int    `json:"userId"`
+	Tag    string `json:"tag"`
+	Games  []Game `json:"games"`
+}
+
+type Transfer struct {
+	GameId int    `json:"gameId"`
+	Status string `json:"status"`
+}
+
+type Transfers struct {
+	UserId          int        `json:"userId"`
+	LibraryId       int        `json:"libraryId"`
+	TargetLibraryId int        `json:"targetLibraryId"`
+	Transfers       []Transfer `json:"transfers"`
+}
+
+type ImportRow struct {
+	Line   int    `json:"line"`
+	Name   string `json:"name"`
+	GameId int    `json:"gameId"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+type Import struct {
+	UserId    int         `json:"userId"`
+	LibraryId int         `json:"libraryId"`
+	DryRun    bool        `json:"dryRun"`
+	Rows      []ImportRow `json:"rows"`
+}
+
+type ExportedGame struct {
+	LibraryId int      `json:"libraryId"`
+	GameId    int      `json:"gameId"`
+	Name      string   `json:"name"`
+	Producer  string   `json:"producer"`
+	Value     float64  `json:"value"`
+	Tags      []string `json:"tags"`
+}
+
+type Player struct {
+	Id         int    `json:"playerId"`
+	Name       string `json:"name"`
+	AccountIds []int  `json:"accountIds"`
+	Accounts   []User `json:"accounts,omitempty"`
+}
+
+type Erasure struct {
+	Id           int    `json:"erasureId"`
+	UserId       int    `json:"userId"`
+	PlayerId     int    `json:"playerId"`
+	PlayerErased bool   `json:"playerErased"`
+	ErasedAt     string `json:"erasedAt"`
+}
+
+type AccountGame struct {
+	GameId     int     `json:"gameId"`
+	Name       string  `json:"name"`
+	Producer   string  `json:"producer"`
+	Value      float64 `json:"value"`
+	UserIds    []int   `json:"userIds"`
+	LibraryIds []int   `json:"libraryIds"`
+}
+
+type AccountGames struct {
+	UserId         int           `json:"userId"`
+	DuplicatesOnly bool          `json:"duplicatesOnly"`
+	Games          []AccountGame `json:"games"`
+}
+
+type Share struct {
+	Id        int    `json:"shareId"`
+	UserId    int    `json:"userId"`
+	LibraryId int    `json:"libraryId"`
+	Token     string `json:"token"`
+	CreatedAt string `json:"createdAt"`
+	RevokedAt string `json:"revokedAt"`
+}
+
+type Shares struct {
+	UserId    int     `json:"userId"`
+	LibraryId int     `json:"libraryId"`
+	Shares    []Share `json:"shares"`
+}
+
+type Friend struct {
+	UserId      int    `json:"userId"`
+	Status      string `json:"status"` //friends, sent or received, from the side of the user
+	RequestedAt string `json:"requestedAt"`
+	AcceptedAt  string `json:"acceptedAt"`
+}
+
+type Friends struct {
+	UserId  int      `json:"userId"`
+	Friends []Friend `json:"friends"`
+}
+
+type Follow struct {
+	UserId     int    `json:"userId"`
+	FollowedAt string `json:"followedAt"`
+}
+
+type Follows struct {
+	UserId    int      `json:"userId"`
+	Followers bool     `json:"followers"`
+	Follows   []Follow `json:"follows"`
+}
+
+type GameFriends struct {
+	GameId  int    `json:"gameId"`
+	Friends []User `json:"friends"`
+}
+
+type Event struct {
+	Id        int    `json:"eventId"`
+	Kind      string `json:"kind"`
+	UserId    int    `json:"userId"`
+	LibraryId int    `json:"libraryId"`
+	GameId    int    `json:"gameId"`
+	GameName  string `json:"gameName"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type Feed struct {
+	Limit      int     `json:"limit"`
+	NextCursor int     `json:"nextCursor"` //0 after the last page
+	Events     []Event `json:"events"`
+}
+
+type AuditEntry struct {
+	Id         int    `json:"auditId"`
+	Action     string `json:"action"`
+	ActorId    int    `json:"actorId"`
+	TargetType string `json:"targetType"`
+	TargetId   int    `json:"targetId"`
+	Detail     string `json:"detail"`
+	Ip         string `json:"ip"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type AuditLog struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+type Health struct {
+	Status        string `json:"status"`
+	SchemaVersion int    `json:"schemaVersion,omitempty"`
+}
+
+type Version struct {
+	Commit        string `json:"commit"`
+	BuildTime     string `json:"buildTime"`
+	SchemaVersion int    `json:"schemaVersion"`
+}
@@ -1,19 +1,54 @@
 package routes
 
 import (
-	"fmt"
 	"github.com/gin-gonic/gin"
+	"time"
 
 	"game-tracker/interfaces"
 	"game-tracker/middlewares/auth"
+	"game-tracker/middlewares/cors"
 	"game-tracker/middlewares/errres"
+	"game-tracker/middlewares/logging"
+	"game-tracker/middlewares/metrics"
+	"game-tracker/middlewares/timeout"
+	"game-tracker/middlewares/tracing"
+	"game-tracker/models/postgres"
 	res "game-tracker/models/responses"
+	"game-tracker/models/result"
 )
 
-func CreateEngine(webserviceHandler interfaces.WebserviceHandler) *gin.Engine {
+func CreateEngine(webserviceHandler interfaces.WebserviceHandler, config postgres.Configuration) *gin.Engine {
 	engine := gin.New()
-	engine.Use(gin.Logger(), gin.Recovery())
+	// The configuration is validated, the proxies parse
+	err := engine.SetTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		panic(err)
+	}
+	engine.Use(tracing.Requests(), logging.Requests(webserviceHandler.ProfileInteractor.Loggr),
+		metrics.Requests(), gin.Recovery())
+	engine.Use(cors.Requests(config.CORSOrigins))
 	engine.Use(errres.ErrorHandle())
+	engine.Use(timeout.Requests(requestTimeout(config.RequestTimeout), routeTimeouts(config)))
+
+	engine.GET("/healthz", func(c *gin.Context) {
+		code, message := webserviceHandler.Healthz(c)
+		c.Set("code", code)
+		c.JSON(code, res.ViewHealth("/healthz", message.Status, message.SchemaVersion))
+	})
+
+	engine.GET("/readyz", func(c *gin.Context) {
+		code, message := webserviceHandler.Readyz(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.JSON(code, res.ViewHealth("/readyz", message.Status, message.SchemaVersion))
+		}
+	})
+
+	engine.GET("/version", func(c *gin.Context) {
+		code, message := webserviceHandler.Version(c)
+		c.Set("code", code)
+		c.JSON(code, res.ViewVersion(message.Commit, message.BuildTime, message.SchemaVersion))
+	})
 
 	engine.POST("/login", func(c *gin.Context) {
 		tokenString, code := webserviceHandler.Login(c)
@@ -24,13 +59,16 @@ func CreateEngine(webserviceHandler interfaces.WebserviceHandler) *gin.Engine {
 		}
 	})
 
+	// Who is asking, if anyone, decides what is visible
 	unAuth := engine.Group("/users")
+	unAuth.Use(auth.Identify(config.JWTKeys))
 	unAuth.GET("/:id", func(c *gin.Context) {
 		code, message := webserviceHandler.ShowUser(c)
 		c.Set("code", code)
 		if c.Errors.Last() == nil {
 			libraries := res.ViewLibraries(message.LibraryIds)
-			users := res.ViewUser(message.Id, message.Name, libraries)
+			users := res.ViewUser(message.Id, message.Name, message.Visibility, message.Version,
+				libraries)
 			c.JSON(200, users)
 		}
 	})
@@ -38,7 +76,7 @@ func CreateEngine(webserviceHandler interfaces.WebserviceHandler) *gin.Engine {
 		code, message := webserviceHandler.AddUser(c)
 		c.Set("code", code)
 		if c.Errors.Last() == nil {
-			users := res.ViewUser(message.Id, message.Name, nil)
+			users := res.ViewUser(message.Id, message.Name, "", 0, nil)
 			c.JSON(201, users)
 		}
 	})
@@ -51,8 +89,166 @@ func CreateEngine(webserviceHandler interfaces.WebserviceHandler) *gin.Engine {
 		}
 	})
 
+	unAuth.GET("/:id/score", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowGamerScore(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			score := res.ViewScore(message.UserId, message.PlayerId, message.Score)
+			c.JSON(200, score)
+		}
+	})
+
+	unAuth.GET("/:id/friends", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowFriends(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var friends []res.Data
+			for _, f := range message.Friends {
+				friends = append(friends, res.ViewFriend(f.UserId, f.Status, f.RequestedAt,
+					f.AcceptedAt))
+			}
+			c.JSON(200, res.ViewFriends(message.UserId, friends))
+		}
+	})
+	follows := func(handle func(c *gin.Context) (int, result.Follows)) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			code, message := handle(c)
+			c.Set("code", code)
+			if c.Errors.Last() == nil {
+				var follows []res.Data
+				for _, f := range message.Follows {
+					follows = append(follows, res.ViewFollow(f.UserId, f.FollowedAt))
+				}
+				c.JSON(200, res.ViewFollows(message.UserId, message.Followers, follows))
+			}
+		}
+	}
+	unAuth.GET("/:id/following", follows(webserviceHandler.ShowFollowing))
+	unAuth.GET("/:id/followers", follows(webserviceHandler.ShowFollowers))
+
+	feed := func(handle func(c *gin.Context) (int, result.Feed)) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			code, message := handle(c)
+			c.Set("code", code)
+			if c.Errors.Last() == nil {
+				var events []res.Data
+				for _, e := range message.Events {
+					events = append(events, res.ViewEvent(e.Id, e.Kind, e.UserId, e.LibraryId,
+						e.GameId, e.GameName, e.CreatedAt))
+				}
+				c.JSON(200, res.ViewFeed(c.Request.URL.Path, message.Limit, message.NextCursor,
+					events))
+			}
+		}
+	}
+	unAuth.GET("/:id/feed", feed(webserviceHandler.ShowFeed))
+	engine.GET("/feed", auth.Authenticate(config.JWTKeys), feed(webserviceHandler.ShowFollowedFeed))
+
+	unAuth.GET("/:id/libraries/:libId", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowLibrary(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			games := res.ViewGames(message.GamesIds)
+			library := res.ViewLibrary(message.UserId, message.Id, message.Name,
+				message.Description, message.Visibility, message.Version, games)
+			c.JSON(200, library)
+		}
+	})
+	unAuth.GET("/:id/libraries/:libId/games/:gameId", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowGame(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			game := res.ViewGame(message.UserId, message.LibraryId, message.Id,
+				message.Name, message.Producer, message.Value, message.LowestValue, message.Tags,
+				message.Version)
+			c.JSON(code, game)
+		}
+	})
+
+	engine.GET("/shared/:token", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowSharedLibrary(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			games := res.ViewGames(message.GamesIds)
+			library := res.ViewLibrary(message.UserId, message.Id, message.Name,
+				message.Description, message.Visibility, message.Version, games)
+			c.JSON(200, library)
+		}
+	})
+
+	catalog := engine.Group("/games")
+	catalog.GET("/:gameId/prices", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowPrices(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var prices []res.Data
+			for _, p := range message.Prices {
+				prices = append(prices, res.ViewPrice(message.GameId, p.Value, p.Source, p.RecordedAt))
+			}
+			c.JSON(200, res.ViewPrices(message.GameId, message.Value, message.LowestValue, prices))
+		}
+	})
+	catalog.GET("/:gameId/friends", auth.Authenticate(config.JWTKeys), func(c *gin.Context) {
+		code, message := webserviceHandler.ShowOwningFriends(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var friends []res.Data
+			for _, f := range message.Friends {
+				friends = append(friends, res.Data{Type: "users", Id: f.Id,
+					Attributes: res.Attributes{Name: f.Name}})
+			}
+			c.JSON(200, res.ViewGameFriends(message.GameId, friends))
+		}
+	})
+
+	engine.GET("/audit", auth.Authenticate(config.JWTKeys), func(c *gin.Context) {
+		code, message := webserviceHandler.ShowAuditLog(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var entries []res.Data
+			for _, e := range message.Entries {
+				entries = append(entries, res.ViewAuditEntry(e.Id, e.Action, e.ActorId,
+					e.TargetType, e.TargetId, e.Detail, e.Ip, e.CreatedAt))
+			}
+			c.JSON(200, res.ViewAuditLog(entries))
+		}
+	})
+
+	players := engine.Group("/players")
+	players.Use(auth.Identify(config.JWTKeys))
+	players.GET("/:playerId", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowPlayer(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.JSON(200, res.ViewPlayer(message.Id, message.Name, viewAccounts(message.Accounts)))
+		}
+	})
+	players.GET("/:playerId/users", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowPlayer(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.JSON(200, res.ViewPlayerUsers(message.Id, viewAccounts(message.Accounts)))
+		}
+	})
+	player := players.Group("/:playerId")
+	player.Use(auth.Authenticate(config.JWTKeys))
+	player.PUT("/name", func(c *gin.Context) {
+		code, message := webserviceHandler.RenamePlayer(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.JSON(200, res.ViewPlayer(message.Id, message.Name, nil))
+		}
+	})
+	player.POST("/merge", func(c *gin.Context) {
+		code, message := webserviceHandler.MergePlayers(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.JSON(200, res.ViewPlayer(message.Id, message.Name, viewAccounts(message.Accounts)))
+		}
+	})
+
 	authorized := engine.Group("/users/:id")
-	authorized.Use(auth.CheckToken())
+	authorized.Use(auth.CheckToken(config.JWTKeys))
 
 	users := authorized.Group("")
 	users.DELETE("", func(c *gin.Context) {
@@ -62,6 +258,26 @@ func CreateEngine(webserviceHandler interfaces.WebserviceHandler) *gin.Engine {
 			c.Status(204)
 		}
 	})
+	users.POST("/restore", func(c *gin.Context) {
+		code, message := webserviceHandler.RestoreUser(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			libraries := res.ViewLibraries(message.LibraryIds)
+			user := res.ViewUser(message.Id, message.Name, message.Visibility, message.Version,
+				libraries)
+			c.JSON(200, user)
+		}
+	})
+	users.PATCH("", func(c *gin.Context) {
+		code, message := webserviceHandler.EditUser(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			libraries := res.ViewLibraries(message.LibraryIds)
+			user := res.ViewUser(message.Id, message.Name, message.Visibility, message.Version,
+				libraries)
+			c.JSON(200, user)
+		}
+	})
 	users.PUT("/info", func(c *gin.Context) {
 		code, message := webserviceHandler.EditUserInfo(c)
 		c.Set("code", code)
@@ -70,25 +286,38 @@ func CreateEngine(webserviceHandler interfaces.WebserviceHandler) *gin.Engine {
 			c.JSON(201, info)
 		}
 	})
-
-	libraries := users.Group("/libraries")
-	libraries.GET("/:libId", func(c *gin.Context) {
-		code, message := webserviceHandler.ShowLibrary(c)
+	users.GET("/data", func(c *gin.Context) {
+		code := webserviceHandler.ExportPersonalData(c)
+		c.Set("code", code)
+	})
+	users.DELETE("/data", func(c *gin.Context) {
+		code, message := webserviceHandler.EraseUser(c)
 		c.Set("code", code)
 		if c.Errors.Last() == nil {
-			games := res.ViewGames(message.GamesIds)
-			library := res.ViewLibrary(message.UserId, message.Id, games)
-			c.JSON(200, library)
+			c.JSON(200, message)
 		}
 	})
+
+	libraries := users.Group("/libraries")
 	libraries.POST("", func(c *gin.Context) {
 		code, message := webserviceHandler.AddLibrary(c)
 		c.Set("code", code)
 		if c.Errors.Last() == nil {
-			library := res.ViewLibrary(message.UserId, message.Id, nil)
+			library := res.ViewLibrary(message.UserId, message.Id, message.Name,
+				message.Description, "", 0, nil)
 			c.JSON(201, library)
 		}
 	})
+	libraries.PATCH("/:libId", func(c *gin.Context) {
+		code, message := webserviceHandler.EditLibrary(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			games := res.ViewGames(message.GamesIds)
+			library := res.ViewLibrary(message.UserId, message.Id, message.Name,
+				message.Description, message.Visibility, message.Version, games)
+			c.JSON(200, library)
+		}
+	})
 	libraries.DELETE("/:libId", func(c *gin.Context) {
 		code, _ := webserviceHandler.RemoveLibrary(c)
 		c.Set("code", code)
@@ -96,24 +325,52 @@ func CreateEngine(webserviceHandler interfaces.WebserviceHandler) *gin.Engine {
 			c.Status(204)
 		}
 	})
-
-	games := libraries.Group("/:libId/games")
-	games.GET(":gameId", func(c *gin.Context) {
-		code, message := webserviceHandler.ShowGame(c)
+	libraries.POST("/:libId/restore", func(c *gin.Context) {
+		code, message := webserviceHandler.RestoreLibrary(c)
 		c.Set("code", code)
 		if c.Errors.Last() == nil {
-			game := res.ViewGame(message.UserId, message.LibraryId, message.Id,
-				message.Name, message.Producer, message.Value)
-			c.JSON(code, game)
+			games := res.ViewGames(message.GamesIds)
+			library := res.ViewLibrary(message.UserId, message.Id, message.Name,
+				message.Description, message.Visibility, message.Version, games)
+			c.JSON(200, library)
+		}
+	})
+	libraries.POST("/:libId/shares", func(c *gin.Context) {
+		code, message := webserviceHandler.ShareLibrary(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.JSON(201, res.ViewShare(message.UserId, message.LibraryId, message.Id,
+				message.Token, message.CreatedAt, message.RevokedAt))
+		}
+	})
+	libraries.GET("/:libId/shares", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowShares(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var shares []res.Data
+			for _, s := range message.Shares {
+				shares = append(shares, res.ViewShare(message.UserId, message.LibraryId, s.Id,
+					s.Token, s.CreatedAt, s.RevokedAt).Data)
+			}
+			c.JSON(200, res.ViewShares(message.UserId, message.LibraryId, shares))
+		}
+	})
+	libraries.DELETE("/:libId/shares/:shareId", func(c *gin.Context) {
+		code := webserviceHandler.RevokeShare(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.Status(204)
 		}
 	})
+
+	games := libraries.Group("/:libId/games")
 	games.POST("", func(c *gin.Context) {
 		code, message := webserviceHandler.AddGame(c)
 		c.Set("code", code)
-		fmt.Printf("err: %v\n", c.Errors)
 		if c.Errors.Last() == nil {
 			game := res.ViewGame(message.UserId, message.LibraryId, message.Id,
-				message.Name, message.Producer, message.Value)
+				message.Name, message.Producer, message.Value, message.LowestValue, message.Tags,
+				message.Version)
 			c.JSON(code, game)
 		}
 	})
@@ -121,7 +378,7 @@ func CreateEngine(webserviceHandler interfaces.WebserviceHandler) *gin.Engine {
 		code, message := webserviceHandler.PickGame(c)
 		c.Set("code", code)
 		if c.Errors.Last() == nil {
-			game := res.ViewGame(message.UserId, message.LibraryId, message.Id, "", "", 0)
+			game := res.ViewGame(message.UserId, message.LibraryId, message.Id, "", "", 0, 0, nil, 0)
 			c.JSON(code, game)
 		}
 	})
@@ -132,5 +389,269 @@ func CreateEngine(webserviceHandler interfaces.WebserviceHandler) *gin.Engine {
 			c.Status(204)
 		}
 	})
+
+	achievements := users.Group("")
+	achievements.GET("/games/:gameId/achievements", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowAchievements(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var data []res.Data
+			for _, a := range message.Achievements {
+				data = append(data, res.ViewAchievementData(a.GameId, a.Id, a.Name,
+					a.Description, a.Points, a.Hidden, a.Unlocked, a.UnlockedAt))
+			}
+			list := res.ViewAchievements(message.UserId, message.GameId, message.Completion, data)
+			c.JSON(200, list)
+		}
+	})
+	achievements.POST("/games/:gameId/achievements", func(c *gin.Context) {
+		code, message := webserviceHandler.AddAchievement(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			achievement := res.ViewAchievement(message.UserId, message.GameId, message.Id,
+				message.Name, message.Description, message.Points, message.Hidden, false, "")
+			c.JSON(201, achievement)
+		}
+	})
+	achievements.POST("/achievements/:achId", func(c *gin.Context) {
+		code, message := webserviceHandler.UnlockAchievement(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			achievement := res.ViewAchievement(message.UserId, message.GameId, message.Id,
+				message.Name, message.Description, message.Points, message.Hidden,
+				message.Unlocked, message.UnlockedAt)
+			c.JSON(201, achievement)
+		}
+	})
+
+	wishlist := users.Group("/wishlist")
+	wishlist.GET("", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowWishlist(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var entries []res.Data
+			for _, e := range message.Entries {
+				entries = append(entries, res.ViewWishlistEntry(e.GameId, e.TargetPrice, e.AddedAt))
+			}
+			c.JSON(200, res.ViewWishlist(message.UserId, entries))
+		}
+	})
+	wishlist.POST("/:gameId", func(c *gin.Context) {
+		code, message := webserviceHandler.AddToWishlist(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			entry := res.ViewWishlistEntry(message.GameId, message.TargetPrice, message.AddedAt)
+			c.JSON(201, res.ViewWishlist(message.UserId, []res.Data{entry}))
+		}
+	})
+	wishlist.DELETE("/:gameId", func(c *gin.Context) {
+		code, _ := webserviceHandler.RemoveFromWishlist(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.Status(204)
+		}
+	})
+	users.GET("/inbox", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowInbox(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var notifications []res.Data
+			for _, n := range message.Notifications {
+				notifications = append(notifications, res.ViewNotification(n.Id, n.GameId,
+					n.Message, n.Value, n.CreatedAt))
+			}
+			c.JSON(200, res.ViewInbox(message.UserId, notifications))
+		}
+	})
+	users.PUT("/games/:gameId/value", func(c *gin.Context) {
+		code, message := webserviceHandler.UpdateGameValue(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			game := res.ViewCatalogGame(message.Id, message.Name, message.Producer, message.Value,
+				message.Version)
+			c.JSON(200, game)
+		}
+	})
+
+	games.POST("/:gameId/tags", func(c *gin.Context) {
+		code, message := webserviceHandler.TagGame(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			game := res.ViewGame(message.UserId, message.LibraryId, message.GameId, "", "", 0, 0,
+				message.Tags, 0)
+			c.JSON(201, game)
+		}
+	})
+	games.DELETE("/:gameId/tags/:tag", func(c *gin.Context) {
+		code, _ := webserviceHandler.UntagGame(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.Status(204)
+		}
+	})
+	users.GET("/games", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowUserGames(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var games []res.Game
+			for _, g := range message.Games {
+				games = append(games, res.ViewGame(g.UserId, g.LibraryId, g.Id, g.Name,
+					g.Producer, g.Value, 0, g.Tags, 0))
+			}
+			c.JSON(200, res.ViewUserGames(message.UserId, games))
+		}
+	})
+	friends := users.Group("/friends")
+	friends.POST("", func(c *gin.Context) {
+		code, message := webserviceHandler.RequestFriend(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			friend := res.ViewFriend(message.UserId, message.Status, message.RequestedAt,
+				message.AcceptedAt)
+			c.JSON(201, res.ViewFriends(c.GetInt("userId"), []res.Data{friend}))
+		}
+	})
+	friends.PUT("/:friendId", func(c *gin.Context) {
+		code, message := webserviceHandler.AcceptFriend(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			friend := res.ViewFriend(message.UserId, message.Status, message.RequestedAt,
+				message.AcceptedAt)
+			c.JSON(200, res.ViewFriends(c.GetInt("userId"), []res.Data{friend}))
+		}
+	})
+	friends.DELETE("/:friendId", func(c *gin.Context) {
+		code := webserviceHandler.RemoveFriend(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.Status(204)
+		}
+	})
+	following := users.Group("/following")
+	following.PUT("/:followeeId", func(c *gin.Context) {
+		code, message := webserviceHandler.FollowUser(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			follow := res.ViewFollow(message.UserId, message.FollowedAt)
+			c.JSON(200, res.ViewFollows(c.GetInt("userId"), false, []res.Data{follow}))
+		}
+	})
+	following.DELETE("/:followeeId", func(c *gin.Context) {
+		code := webserviceHandler.UnfollowUser(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.Status(204)
+		}
+	})
+	accounts := users.Group("/accounts")
+	accounts.GET("", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowAccounts(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.JSON(200, res.ViewPlayer(message.Id, message.Name, viewAccounts(message.Accounts)))
+		}
+	})
+	accounts.POST("", func(c *gin.Context) {
+		code, message := webserviceHandler.LinkAccount(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.JSON(201, res.ViewPlayer(message.Id, message.Name, viewAccounts(message.Accounts)))
+		}
+	})
+	accounts.GET("/games", func(c *gin.Context) {
+		code, message := webserviceHandler.ShowAccountGames(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			var games []res.Data
+			for _, g := range message.Games {
+				games = append(games, res.ViewAccountGame(g.GameId, g.Name, g.Producer, g.Value,
+					g.UserIds, g.LibraryIds))
+			}
+			c.JSON(200, res.ViewAccountGames(message.UserId, games))
+		}
+	})
+	users.GET("/tags", func(c *gin.Context) {
+		code, message := webserviceHandler.SuggestTags(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			c.JSON(200, res.ViewTags(message.UserId, message.Tags))
+		}
+	})
+	users.PATCH("/games/:gameId", func(c *gin.Context) {
+		code, message := webserviceHandler.EditGame(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			game := res.ViewCatalogGame(message.Id, message.Name, message.Producer, message.Value,
+				message.Version)
+			c.JSON(200, game)
+		}
+	})
+
+	transfer := func(handle func(c *gin.Context) (int, result.Transfers)) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			code, message := handle(c)
+			c.Set("code", code)
+			if c.Errors.Last() == nil {
+				var transfers []res.Data
+				for _, t := range message.Transfers {
+					transfers = append(transfers, res.ViewTransfer(message.TargetLibraryId,
+						t.GameId, t.Status))
+				}
+				c.JSON(200, res.ViewTransfers(message.UserId, message.LibraryId,
+					message.TargetLibraryId, transfers))
+			}
+		}
+	}
+	games.POST("/:gameId/move", transfer(webserviceHandler.MoveGame))
+	games.POST("/:gameId/copy", transfer(webserviceHandler.CopyGame))
+	libraries.POST("/:libId/move", transfer(webserviceHandler.MoveGames))
+	libraries.POST("/:libId/copy", transfer(webserviceHandler.CopyGames))
+	libraries.POST("/:libId/import", func(c *gin.Context) {
+		code, message := webserviceHandler.ImportGames(c)
+		c.Set("code", code)
+		if c.Errors.Last() == nil {
+			counts := make(map[string]int)
+			var rows []res.Data
+			for _, r := range message.Rows {
+				counts[r.Status]++
+				rows = append(rows, res.ViewImportRow(r.Line, r.GameId, r.Name, r.Status, r.Reason))
+			}
+			c.JSON(200, res.ViewImport(message.UserId, message.LibraryId, message.DryRun,
+				counts, rows))
+		}
+	})
+	export := func(c *gin.Context) {
+		code := webserviceHandler.ExportGames(c)
+		c.Set("code", code)
+	}
+	users.GET("/export", export)
+	libraries.GET("/:libId/export", export)
 	return engine
 }
+
+func viewAccounts(accounts []result.User) []res.User {
+	var users []res.User
+	for _, account := range accounts {
+		libraries := res.ViewLibraries(account.LibraryIds)
+		users = append(users, res.ViewUser(account.Id, account.Name, account.Visibility,
+			account.Version, libraries))
+	}
+	return users
+}
+
+// requestTimeout turns seconds from the configuration into a timeout, 30
+// seconds when unset
+func requestTimeout(seconds int) time.Duration {
+	if seconds == 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func routeTimeouts(config postgres.Configuration) map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for route, seconds := range config.RouteTimeouts {
+		timeouts[route] = time.Duration(seconds) * time.Second
+	}
+	return timeouts
+}
@@ -0,0 +1,198 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Who can see a user profile or a library besides its owner
+const (
+	VisibilityPrivate = "private"
+	VisibilityFriends = "friends"
+	VisibilityPublic  = "public"
+)
+
+type ShareRepository interface {
+	Store(ctx context.Context, share Share) (int, error)
+	FindByLibrary(ctx context.Context, libraryId int) ([]Share, error)
+	FindByToken(ctx context.Context, token string) (Share, error, int)
+	Revoke(ctx context.Context, libraryId, shareId int, revokedAt time.Time) (error, int)
+}
+
+// A link giving anyone holding its token a look at a library, whatever the
+// visibility of the library
+type Share struct {
+	Id        int
+	LibraryId int
+	Token     string
+	CreatedAt time.Time
+	RevokedAt *time.Time //nil while the link works
+}
+
+func checkVisibility(visibility string) error {
+	switch visibility {
+	case VisibilityPrivate, VisibilityFriends, VisibilityPublic:
+		return nil
+	}
+	return fmt.Errorf("Visibility must be %s, %s or %s", VisibilityPrivate, VisibilityFriends,
+		VisibilityPublic)
+}
+
+// canSee tells if the viewer can see what the owner shows with the given
+// visibility. A viewerId of 0 is an anonymous viewer.
+func (interactor *ProfileInteractor) canSee(ctx context.Context, viewerId, ownerId int, visibility string) (bool, error) {
+	if viewerId == ownerId && viewerId != 0 {
+		return true, nil
+	}
+	switch visibility {
+	case VisibilityPublic:
+		return true, nil
+	case VisibilityFriends:
+		if viewerId == 0 {
+			return false, nil
+		}
+		return interactor.areFriends(ctx, viewerId, ownerId)
+	}
+	return false, nil
+}
+
+func (interactor *ProfileInteractor) areFriends(ctx context.Context, userId, otherUserId int) (bool, error) {
+	return interactor.FriendRepository.AreFriends(ctx, userId, otherUserId)
+}
+
+func (interactor *ProfileInteractor) checkUserVisible(ctx context.Context, viewerId int, user User) (error, int) {
+	visible, err := interactor.canSee(ctx, viewerId, user.Id, user.Visibility)
+	if err != nil {
+		return err, 500
+	}
+	if !visible {
+		return fmt.Errorf("User #%d is not allowed to see user #%d", viewerId, user.Id), 403
+	}
+	return nil, 200
+}
+
+func (interactor *ProfileInteractor) checkLibraryVisible(ctx context.Context, viewerId int, library Library) (error, int) {
+	visible, err := interactor.canSee(ctx, viewerId, library.User.Id, library.Visibility)
+	if err != nil {
+		return err, 500
+	}
+	if !visible {
+		message := "User #%d is not allowed to see library #%d of user #%d"
+		return fmt.Errorf(message, viewerId, library.Id, library.User.Id), 403
+	}
+	return nil, 200
+}
+
+// checkLibraryOwner refuses users acting on a library that is not theirs,
+// action tells what they tried in the error
+func checkLibraryOwner(userId int, library Library, action string) (error, int) {
+	if userId != library.User.Id {
+		message := "User #%d is not allowed to %s library #%d of user #%d"
+		return fmt.Errorf(message, userId, action, library.Id, library.User.Id), 403
+	}
+	return nil, 200
+}
+
+// visibleLibraryIds keeps the libraries of the user the viewer can see
+func (interactor *ProfileInteractor) visibleLibraryIds(ctx context.Context, viewerId int, user User) ([]int, error) {
+	if viewerId == user.Id {
+		return user.LibraryIds, nil
+	}
+	libraryIds := []int{}
+	for _, libraryId := range user.LibraryIds {
+		library, err, _ := interactor.LibraryRepository.FindById(ctx, libraryId)
+		if err != nil {
+			return nil, err
+		}
+		visible, err := interactor.canSee(ctx, viewerId, user.Id, library.Visibility)
+		if err != nil {
+			return nil, err
+		}
+		if visible {
+			libraryIds = append(libraryIds, libraryId)
+		}
+	}
+	return libraryIds, nil
+}
+
+func (interactor *ProfileInteractor) ShareLibrary(ctx context.Context, userId, libraryId int) (Share, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShareLibrary")
+	defer end()
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
+	if err != nil {
+		return Share{}, err, code
+	}
+	err, code = checkLibraryOwner(userId, library, "share")
+	if err != nil {
+		return Share{}, err, code
+	}
+
+	token := make([]byte, 24)
+	_, err = rand.Read(token)
+	if err != nil {
+		return Share{}, err, 500
+	}
+	share := Share{LibraryId: library.Id, Token: hex.EncodeToString(token),
+		CreatedAt: time.Now().UTC()}
+	share.Id, err = interactor.ShareRepository.Store(ctx, share)
+	if err != nil {
+		return Share{}, err, 500
+	}
+	interactor.Loggr.Info("Library shared", "libraryId", library.Id, "shareId", share.Id)
+	return share, nil, 201
+}
+
+func (interactor *ProfileInteractor) ShowShares(ctx context.Context, userId, libraryId int) ([]Share, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowShares")
+	defer end()
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
+	if err != nil {
+		return nil, err, code
+	}
+	err, code = checkLibraryOwner(userId, library, "see the shares of")
+	if err != nil {
+		return nil, err, code
+	}
+	shares, err := interactor.ShareRepository.FindByLibrary(ctx, library.Id)
+	if err != nil {
+		return nil, err, 500
+	}
+	return shares, nil, 200
+}
+
+func (interactor *ProfileInteractor) RevokeShare(ctx context.Context, userId, libraryId, shareId int) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RevokeShare")
+	defer end()
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
+	if err != nil {
+		return err, code
+	}
+	err, code = checkLibraryOwner(userId, library, "revoke the shares of")
+	if err != nil {
+		return err, code
+	}
+	err, code = interactor.ShareRepository.Revoke(ctx, library.Id, shareId, time.Now().UTC())
+	if err != nil {
+		return err, code
+	}
+	interactor.Loggr.Info("Share revoked", "libraryId", library.Id, "shareId", shareId)
+	return nil, 200
+}
+
+// ShowSharedLibrary finds the library a share link points at. Revoked links
+// are not found.
+func (interactor *ProfileInteractor) ShowSharedLibrary(ctx context.Context, token string) (Library, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowSharedLibrary")
+	defer end()
+	share, err, code := interactor.ShareRepository.FindByToken(ctx, token)
+	if err != nil {
+		return Library{}, err, code
+	}
+	if share.RevokedAt != nil {
+		return Library{}, fmt.Errorf("Share link does not exist"), 404
+	}
+	return interactor.LibraryRepository.FindById(ctx, share.LibraryId)
+}
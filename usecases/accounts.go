@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"game-tracker/domain"
+)
+
+// A game owned by some of the accounts of a player
+type AccountGame struct {
+	Game       Game
+	UserIds    []int //Accounts owning the game
+	LibraryIds []int //Libraries listing the game, across those accounts
+}
+
+// LinkAccount moves another account under the player of the user. The user
+// proves the other account is theirs with its credentials. A player left
+// without accounts is merged into the user's player.
+func (interactor *ProfileInteractor) LinkAccount(ctx context.Context, userId int, username, password string) (domain.Player, []User, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "LinkAccount")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return domain.Player{}, nil, err, code
+	}
+	other, err, code := interactor.credentialsUser(ctx, username, password)
+	if err != nil {
+		return domain.Player{}, nil, err, code
+	}
+	if other.Player.Id == user.Player.Id {
+		err = fmt.Errorf("User #%d is already an account of player #%d", other.Id, user.Player.Id)
+		return domain.Player{}, nil, err, 400
+	}
+
+	err = interactor.PlayerRepository.Link(ctx, user.Player.Id, other)
+	if err != nil {
+		return domain.Player{}, nil, err, 500
+	}
+	interactor.Loggr.Info("Account linked", "linkedUserId", other.Id,
+		"playerId", user.Player.Id)
+	return interactor.ShowPlayer(ctx, user.Id, user.Player.Id)
+}
+
+// ShowAccountGames lists the games of every account of the user's player,
+// once each. With duplicatesOnly, only the games of the user also owned by
+// another account are listed.
+func (interactor *ProfileInteractor) ShowAccountGames(ctx context.Context, userId int, duplicatesOnly bool) ([]AccountGame, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowAccountGames")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err, code
+	}
+	accountIds, err := interactor.UserRepository.FindByPlayer(ctx, user.Player.Id)
+	if err != nil {
+		return nil, err, 500
+	}
+
+	var games []*AccountGame
+	byId := make(map[int]*AccountGame)
+	for _, accountId := range accountIds {
+		err = interactor.GameRepository.EachInLibraries(ctx, accountId, 0, func(entry LibraryEntry) error {
+			game, ok := byId[entry.Game.Id]
+			if !ok {
+				entry.Game.Tags = nil
+				game = &AccountGame{Game: entry.Game}
+				byId[entry.Game.Id] = game
+				games = append(games, game)
+			}
+			if n := len(game.UserIds); n == 0 || game.UserIds[n-1] != accountId {
+				game.UserIds = append(game.UserIds, accountId)
+			}
+			game.LibraryIds = append(game.LibraryIds, entry.LibraryId)
+			return nil
+		})
+		if err != nil {
+			return nil, err, 500
+		}
+	}
+
+	accountGames := []AccountGame{}
+	for _, game := range games {
+		if duplicatesOnly && !(len(game.UserIds) > 1 && containsId(game.UserIds, user.Id)) {
+			continue
+		}
+		accountGames = append(accountGames, *game)
+	}
+	return accountGames, nil, 200
+}
+
+func containsId(ids []int, id int) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type AchievementRepository interface {
+	Store(ctx context.Context, achievement Achievement) (int, error)
+	FindById(ctx context.Context, id int) (Achievement, error, int)
+	FindByGame(ctx context.Context, gameId int) ([]Achievement, error)
+	Unlock(ctx context.Context, playerId, achievementId int, unlockedAt time.Time) (error, int)
+	FindUnlocked(ctx context.Context, playerId, gameId int) (map[int]time.Time, error)
+	GamerScore(ctx context.Context, playerId int) (int, error)
+	FindAllUnlocked(ctx context.Context, playerId int) ([]PlayerAchievement, error)
+}
+
+type Achievement struct {
+	Id          int
+	GameId      int
+	Name        string
+	Description string
+	Points      int
+	Hidden      bool
+}
+
+// An achievement as seen by one player
+type PlayerAchievement struct {
+	Achievement
+	Unlocked   bool
+	UnlockedAt time.Time
+}
+
+func (interactor *ProfileInteractor) AddAchievement(ctx context.Context, userId, gameId int, name, description string, points int, hidden bool) (int, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "AddAchievement")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return 0, err, code
+	}
+	_, err, code = interactor.GameRepository.FindById(ctx, gameId)
+	if err != nil {
+		return 0, err, code
+	}
+	// Application rule: only players owning a game can define its achievements
+	owned, err := interactor.GameRepository.InUserLibraries(ctx, gameId, user.Id)
+	if err != nil {
+		return 0, err, 500
+	}
+	if !owned {
+		err := fmt.Errorf("User #%d does not own game #%d", user.Id, gameId)
+		return 0, err, 403
+	}
+	if points < 0 {
+		err := fmt.Errorf("Achievement points cannot be negative")
+		return 0, err, 400
+	}
+
+	achievement := Achievement{GameId: gameId, Name: name, Description: description,
+		Points: points, Hidden: hidden}
+	id, err := interactor.AchievementRepository.Store(ctx, achievement)
+	if err != nil {
+		return 0, err, 500
+	}
+	interactor.Loggr.Info("Achievement added", "achievementId", id, "gameId", gameId)
+	return id, nil, 201
+}
+
+func (interactor *ProfileInteractor) UnlockAchievement(ctx context.Context, userId, achievementId int) (PlayerAchievement, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "UnlockAchievement")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return PlayerAchievement{}, err, code
+	}
+	achievement, err, code := interactor.AchievementRepository.FindById(ctx, achievementId)
+	if err != nil {
+		return PlayerAchievement{}, err, code
+	}
+	owned, err := interactor.GameRepository.InUserLibraries(ctx, achievement.GameId, user.Id)
+	if err != nil {
+		return PlayerAchievement{}, err, 500
+	}
+	if !owned {
+		message := "User #%d cannot unlock achievements of game #%d without owning it"
+		err := fmt.Errorf(message, user.Id, achievement.GameId)
+		return PlayerAchievement{}, err, 403
+	}
+
+	unlockedAt := time.Now().UTC()
+	err, code = interactor.AchievementRepository.Unlock(ctx, user.Player.Id, achievement.Id, unlockedAt)
+	if err != nil {
+		return PlayerAchievement{}, err, code
+	}
+	interactor.Loggr.Info("Achievement unlocked", "achievementId", achievement.Id,
+		"playerId", user.Player.Id)
+	return PlayerAchievement{Achievement: achievement, Unlocked: true, UnlockedAt: unlockedAt}, nil, 201
+}
+
+// ShowAchievements lists the achievements of a game from the point of view of
+// the user's player, along with the player's completion percentage of the game.
+// Hidden achievements keep their name and description secret until unlocked.
+func (interactor *ProfileInteractor) ShowAchievements(ctx context.Context, userId, gameId int) ([]PlayerAchievement, float64, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowAchievements")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, 0, err, code
+	}
+	_, err, code = interactor.GameRepository.FindById(ctx, gameId)
+	if err != nil {
+		return nil, 0, err, code
+	}
+	achievements, err := interactor.AchievementRepository.FindByGame(ctx, gameId)
+	if err != nil {
+		return nil, 0, err, 500
+	}
+	unlocked, err := interactor.AchievementRepository.FindUnlocked(ctx, user.Player.Id, gameId)
+	if err != nil {
+		return nil, 0, err, 500
+	}
+
+	var playerAchievements []PlayerAchievement
+	for _, achievement := range achievements {
+		playerAchievement := PlayerAchievement{Achievement: achievement}
+		playerAchievement.UnlockedAt, playerAchievement.Unlocked = unlocked[achievement.Id]
+		if achievement.Hidden && !playerAchievement.Unlocked {
+			playerAchievement.Name = ""
+			playerAchievement.Description = ""
+		}
+		playerAchievements = append(playerAchievements, playerAchievement)
+	}
+
+	var completion float64
+	if len(achievements) > 0 {
+		completion = float64(len(unlocked)) / float64(len(achievements)) * 100
+	}
+	return playerAchievements, completion, nil, 200
+}
+
+// ShowGamerScore sums the points of the achievements unlocked by the user's
+// player, counting only games present in a library of any of the player's users.
+func (interactor *ProfileInteractor) ShowGamerScore(ctx context.Context, viewerId, userId int) (int, int, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowGamerScore")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		err = fmt.Errorf("User #%d does not exist", userId)
+		return 0, 0, err, code
+	}
+	err, code = interactor.checkUserVisible(ctx, viewerId, user)
+	if err != nil {
+		return 0, 0, err, code
+	}
+	score, err := interactor.AchievementRepository.GamerScore(ctx, user.Player.Id)
+	if err != nil {
+		return 0, 0, err, 500
+	}
+	return user.Player.Id, score, nil, 200
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// What the audit log records
+const (
+	AuditLoginSucceeded = "login_succeeded"
+	AuditLoginFailed    = "login_failed"
+	AuditUserCreated    = "user_created"
+	AuditUserRemoved    = "user_removed"
+	AuditUserRestored   = "user_restored"
+	AuditUserErased     = "user_erased"
+	AuditRemovedPurged  = "removed_purged"
+	AuditLibraryRemoved = "library_removed"
+	AuditGameRemoved    = "game_removed"
+	AuditLogQueried     = "audit_queried"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditRepository interface {
+	Store(ctx context.Context, entry AuditEntry) error
+	Find(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+}
+
+type AuditEntry struct {
+	Id         int
+	Action     string
+	ActorId    int //0 for anonymous requests and the application itself
+	TargetType string
+	TargetId   int
+	Detail     string
+	Ip         string
+	CreatedAt  time.Time
+}
+
+// Entries of the actor, with the action, between From and To, newest first.
+// An ActorId of 0 and an empty Action match any, a Limit of 0 finds them all.
+type AuditFilter struct {
+	ActorId int
+	Action  string
+	From    time.Time
+	To      time.Time
+	Limit   int
+}
+
+func (interactor *ProfileInteractor) audit(ctx context.Context, action string, actorId int, targetType string, targetId int, detail string) error {
+	entry := AuditEntry{Action: action, ActorId: actorId, TargetType: targetType,
+		TargetId: targetId, Detail: detail, Ip: interactor.Origin, CreatedAt: time.Now().UTC()}
+	err := interactor.AuditRepository.Store(ctx, entry)
+	if err != nil {
+		return err
+	}
+	interactor.Metrics.Count(action)
+	return nil
+}
+
+// Login finds the user logging in with the credentials, failed attempts
+// included in the audit log
+func (interactor *ProfileInteractor) Login(ctx context.Context, username, password string) (int, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "Login")
+	defer end()
+	id, err, code := interactor.FindLoginId(ctx, username, password)
+	if err != nil {
+		if code == 400 {
+			// The attempted username is not kept, it is free text and often a
+			// password typed in the wrong field
+			auditErr := interactor.audit(ctx, AuditLoginFailed, 0, "user", 0, "")
+			if auditErr != nil {
+				return 0, auditErr, 500
+			}
+		}
+		return 0, err, code
+	}
+	err = interactor.audit(ctx, AuditLoginSucceeded, id, "user", id, "")
+	if err != nil {
+		return 0, err, 500
+	}
+	return id, nil, 200
+}
+
+// ShowAuditLog lets admins search the audit log, which records the search too
+func (interactor *ProfileInteractor) ShowAuditLog(ctx context.Context, userId int, filter AuditFilter) ([]AuditEntry, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowAuditLog")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err, code
+	}
+	if !user.Admin {
+		return nil, fmt.Errorf("User #%d is not allowed to read the audit log", user.Id), 403
+	}
+	if filter.To.IsZero() {
+		filter.To = time.Now().UTC()
+	}
+	if filter.To.Before(filter.From) {
+		err := fmt.Errorf("End of the date range is before its start")
+		return nil, err, 400
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxAuditLimit {
+		err := fmt.Errorf("Limit must be between 1 and %d", maxAuditLimit)
+		return nil, err, 400
+	}
+
+	detail := fmt.Sprintf("actor=%d action=%s from=%s to=%s", filter.ActorId, filter.Action,
+		filter.From.Format(time.RFC3339), filter.To.Format(time.RFC3339))
+	err = interactor.audit(ctx, AuditLogQueried, user.Id, "audit", 0, detail)
+	if err != nil {
+		return nil, err, 500
+	}
+	entries, err := interactor.AuditRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, err, 500
+	}
+	return entries, nil, 200
+}
@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Used when the interactor is not given a retention
+const defaultRetention = 30 * 24 * time.Hour
+
+func (interactor *ProfileInteractor) retention() time.Duration {
+	if interactor.Retention <= 0 {
+		return defaultRetention
+	}
+	return interactor.Retention
+}
+
+// RestoreUser brings back a removed user along with the libraries removed
+// with them, as long as the retention period has not passed.
+func (interactor *ProfileInteractor) RestoreUser(ctx context.Context, userId int) (User, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RestoreUser")
+	defer end()
+	user, deletedAt, err, code := interactor.UserRepository.FindDeleted(ctx, userId)
+	if err != nil {
+		return User{}, err, code
+	}
+	if time.Since(deletedAt) > interactor.retention() {
+		err = fmt.Errorf("User #%d was removed too long ago to be restored", userId)
+		return User{}, err, 410
+	}
+
+	err = interactor.UserRepository.Restore(ctx, user, deletedAt)
+	if err != nil {
+		return User{}, err, 500
+	}
+	err = interactor.audit(ctx, AuditUserRestored, user.Id, "user", user.Id, "")
+	if err != nil {
+		return User{}, err, 500
+	}
+	interactor.Loggr.Info("User restored")
+	return interactor.ShowUser(ctx, userId, userId)
+}
+
+func (interactor *ProfileInteractor) RestoreLibrary(ctx context.Context, userId, libraryId int) (Library, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RestoreLibrary")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return Library{}, err, code
+	}
+	library, deletedAt, err, code := interactor.LibraryRepository.FindDeleted(ctx, libraryId)
+	if err != nil {
+		return Library{}, err, code
+	}
+	err, code = checkLibraryOwner(user.Id, library, "restore")
+	if err != nil {
+		return Library{}, err, code
+	}
+	if time.Since(deletedAt) > interactor.retention() {
+		err = fmt.Errorf("Library #%d was removed too long ago to be restored", libraryId)
+		return Library{}, err, 410
+	}
+
+	err = interactor.LibraryRepository.Restore(ctx, library)
+	if err != nil {
+		return Library{}, err, 500
+	}
+	interactor.Loggr.Info("Library restored", "libraryId", libraryId)
+	return interactor.ShowLibrary(ctx, userId, userId, libraryId)
+}
+
+// PurgeRemoved removes for good the users and libraries whose retention
+// period has passed.
+func (interactor *ProfileInteractor) PurgeRemoved(ctx context.Context) error {
+	ctx, end := interactor.Tracer.Start(ctx, "PurgeRemoved")
+	defer end()
+	deletedBefore := time.Now().UTC().Add(-interactor.retention())
+	users, err := interactor.UserRepository.Purge(ctx, deletedBefore)
+	if err != nil {
+		return err
+	}
+	libraries, err := interactor.LibraryRepository.Purge(ctx, deletedBefore)
+	if err != nil {
+		return err
+	}
+	if users > 0 || libraries > 0 {
+		detail := fmt.Sprintf("%d users and %d libraries removed before %s", users, libraries,
+			deletedBefore.Format(time.RFC3339))
+		err = interactor.audit(ctx, AuditRemovedPurged, 0, "user", 0, detail)
+		if err != nil {
+			return err
+		}
+		interactor.Loggr.Info("Removed users and libraries purged", "users", users,
+			"libraries", libraries, "deletedBefore", deletedBefore)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+)
+
+// Edits follow optimistic concurrency: the caller sends the version of the
+// resource it has seen, and the edit is refused with 412 if it has changed
+// since. A nil field is left untouched.
+
+func (interactor *ProfileInteractor) EditUser(ctx context.Context, userId, version int, name, visibility *string) (User, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "EditUser")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return User{}, err, code
+	}
+	err, code = checkVersion("User", user.Id, user.Version, version)
+	if err != nil {
+		return User{}, err, code
+	}
+
+	oldName := user.Name
+	if name != nil && *name != user.Name {
+		if *name == "" {
+			err := fmt.Errorf("Username cannot be empty")
+			return User{}, err, 400
+		}
+		// Application rule: usernames cannot repeat
+		existed, err := interactor.UserRepository.UserExisted(ctx, *name)
+		if err != nil {
+			return User{}, err, 500
+		}
+		if existed {
+			err := fmt.Errorf("Username '%s' is taken", *name)
+			return User{}, err, 400
+		}
+		user.Name = *name
+	}
+	if visibility != nil {
+		err = checkVisibility(*visibility)
+		if err != nil {
+			return User{}, err, 400
+		}
+		user.Visibility = *visibility
+	}
+
+	err, code = interactor.UserRepository.Update(ctx, user, oldName)
+	if err != nil {
+		return User{}, err, code
+	}
+	user.Version++
+	interactor.Loggr.Info("User edited")
+	return user, nil, 200
+}
+
+func (interactor *ProfileInteractor) EditLibrary(ctx context.Context, userId, libraryId, version int, name, description, visibility *string) (Library, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "EditLibrary")
+	defer end()
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
+	if err != nil {
+		return Library{}, err, code
+	}
+	err, code = checkLibraryOwner(userId, library, "edit")
+	if err != nil {
+		return Library{}, err, code
+	}
+	err, code = checkVersion("Library", library.Id, library.Version, version)
+	if err != nil {
+		return Library{}, err, code
+	}
+
+	if name != nil {
+		library.Name = *name
+	}
+	if description != nil {
+		library.Description = *description
+	}
+	if visibility != nil {
+		err = checkVisibility(*visibility)
+		if err != nil {
+			return Library{}, err, 400
+		}
+		library.Visibility = *visibility
+	}
+	err, code = interactor.LibraryRepository.Update(ctx, library)
+	if err != nil {
+		return Library{}, err, code
+	}
+	library.Version++
+	interactor.Loggr.Info("Library edited", "libraryId", library.Id)
+	return library, nil, 200
+}
+
+func (interactor *ProfileInteractor) EditGame(ctx context.Context, userId, gameId, version int, name, producer *string, value *float64) (Game, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "EditGame")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return Game{}, err, code
+	}
+	game, err, code := interactor.GameRepository.FindById(ctx, gameId)
+	if err != nil {
+		return Game{}, err, code
+	}
+	// Application rule: only players owning a game can edit its catalog entry
+	owned, err := interactor.GameRepository.InUserLibraries(ctx, gameId, user.Id)
+	if err != nil {
+		return Game{}, err, 500
+	}
+	if !owned {
+		err := fmt.Errorf("User #%d does not own game #%d", user.Id, gameId)
+		return Game{}, err, 403
+	}
+	err, code = checkVersion("Game", game.Id, game.Version, version)
+	if err != nil {
+		return Game{}, err, code
+	}
+
+	if name != nil && *name != game.Name {
+		if *name == "" {
+			err := fmt.Errorf("Game name cannot be empty")
+			return Game{}, err, 400
+		}
+		// Business rule: the catalog identifies games by name
+		_, existed, err := interactor.GameRepository.FindIdByName(ctx, *name)
+		if err != nil {
+			return Game{}, err, 500
+		}
+		if existed {
+			err := fmt.Errorf("Game '%s' already existed", *name)
+			return Game{}, err, 400
+		}
+		game.Name = *name
+	}
+	if producer != nil {
+		game.Producer = *producer
+	}
+	oldValue := game.Value
+	if value != nil {
+		if *value < 0 {
+			err := fmt.Errorf("Game value cannot be negative")
+			return Game{}, err, 400
+		}
+		game.Value = *value
+	}
+
+	err, code = interactor.GameRepository.Update(ctx, game)
+	if err != nil {
+		return Game{}, err, code
+	}
+	game.Version++
+	if game.Value != oldValue {
+		err = interactor.gameValueChanged(ctx, game, oldValue, PriceSourceUser)
+		if err != nil {
+			return Game{}, err, 500
+		}
+	}
+	interactor.Loggr.Info("Game edited", "gameId", game.Id)
+	return game, nil, 200
+}
+
+func checkVersion(resource string, id, current, expected int) (error, int) {
+	if current != expected {
+		message := "%s #%d was modified (version %d, edit based on version %d)"
+		return fmt.Errorf(message, resource, id, current, expected), 412
+	}
+	return nil, 200
+}
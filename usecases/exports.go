@@ -0,0 +1,31 @@
+package usecases
+
+import "context"
+
+// ExportGames hands every game of the user's libraries to write, one at a
+// time, without loading them all. A libraryId of 0 exports every library.
+func (interactor *ProfileInteractor) ExportGames(ctx context.Context, userId, libraryId int, write func(entry LibraryEntry) error) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ExportGames")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return err, code
+	}
+	if libraryId != 0 {
+		library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
+		if err != nil {
+			return err, code
+		}
+		err, code = checkLibraryOwner(user.Id, library, "export")
+		if err != nil {
+			return err, code
+		}
+	}
+
+	err = interactor.GameRepository.EachInLibraries(ctx, user.Id, libraryId, write)
+	if err != nil {
+		return err, 500
+	}
+	interactor.Loggr.Info("Games exported")
+	return nil, 200
+}
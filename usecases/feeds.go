@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// What can happen in a library
+const (
+	EventGameAdded   = "game_added"
+	EventGameRemoved = "game_removed"
+)
+
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+)
+
+type EventRepository interface {
+	Store(ctx context.Context, event Event) error
+	// Both find the events the viewer can see, older than the one with the id
+	// before, newest first. A before of 0 starts from the newest event, a
+	// limit of 0 finds them all. The follower is the viewer of FindFollowed.
+	FindByUser(ctx context.Context, viewerId, userId, before, limit int) ([]Event, error)
+	FindFollowed(ctx context.Context, followerId, before, limit int) ([]Event, error)
+}
+
+type Event struct {
+	Id        int
+	Kind      string
+	UserId    int //Owner of the library
+	LibraryId int
+	GameId    int
+	GameName  string
+	CreatedAt time.Time
+}
+
+// recordEvent follows a change of a library already stored, a failure only
+// loses the event and is logged
+func (interactor *ProfileInteractor) recordEvent(ctx context.Context, kind string, library Library, gameId int) {
+	event := Event{Kind: kind, UserId: library.User.Id, LibraryId: library.Id, GameId: gameId,
+		CreatedAt: time.Now().UTC()}
+	err := interactor.EventRepository.Store(ctx, event)
+	if err != nil {
+		interactor.Loggr.Warn("Event not recorded", "kind", kind, "libraryId", library.Id,
+			"gameId", gameId, "error", err)
+		return
+	}
+	interactor.Metrics.Count(kind)
+}
+
+// ShowFeed pages through the activity of a user, as far as the viewer can
+// see it. The cursor is the id of the last event of the previous page, 0 for
+// the first page; the next cursor is 0 after the last page.
+func (interactor *ProfileInteractor) ShowFeed(ctx context.Context, viewerId, userId, cursor, limit int) ([]Event, int, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowFeed")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, 0, err, code
+	}
+	err, code = interactor.checkUserVisible(ctx, viewerId, user)
+	if err != nil {
+		return nil, 0, err, code
+	}
+	find := func(before, limit int) ([]Event, error) {
+		return interactor.EventRepository.FindByUser(ctx, viewerId, user.Id, before, limit)
+	}
+	return pageEvents(find, cursor, limit)
+}
+
+// ShowFollowedFeed pages through the activity of the users the user follows
+func (interactor *ProfileInteractor) ShowFollowedFeed(ctx context.Context, userId, cursor, limit int) ([]Event, int, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowFollowedFeed")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, 0, err, code
+	}
+	find := func(before, limit int) ([]Event, error) {
+		return interactor.EventRepository.FindFollowed(ctx, user.Id, before, limit)
+	}
+	return pageEvents(find, cursor, limit)
+}
+
+// pageEvents finds a page of events, the repository leaving out those the
+// viewer cannot see
+func pageEvents(find func(before, limit int) ([]Event, error), cursor, limit int) ([]Event, int, error, int) {
+	if cursor < 0 {
+		return nil, 0, fmt.Errorf("Cursor cannot be negative"), 400
+	}
+	if limit == 0 {
+		limit = defaultFeedLimit
+	}
+	if limit < 0 || limit > maxFeedLimit {
+		err := fmt.Errorf("Limit must be between 1 and %d", maxFeedLimit)
+		return nil, 0, err, 400
+	}
+
+	events, err := find(cursor, limit)
+	if err != nil {
+		return nil, 0, err, 500
+	}
+	if len(events) < limit {
+		return events, 0, nil, 200
+	}
+	return events, events[len(events)-1].Id, nil, 200
+}
@@ -0,0 +1,229 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	FriendshipPending  = "pending"
+	FriendshipAccepted = "accepted"
+)
+
+type FriendRepository interface {
+	Request(ctx context.Context, friendship Friendship) (error, int)
+	Accept(ctx context.Context, userId, requesterId int, acceptedAt time.Time) (error, int)
+	Remove(ctx context.Context, userId, otherUserId int) (error, int)
+	FindByUser(ctx context.Context, userId int) ([]Friendship, error)
+	AreFriends(ctx context.Context, userId, otherUserId int) (bool, error)
+	FindOwningFriends(ctx context.Context, userId, gameId int) ([]int, error)
+	Follow(ctx context.Context, follow Follow) error
+	Unfollow(ctx context.Context, followerId, followeeId int) (error, int)
+	FindFollowing(ctx context.Context, userId int) ([]Follow, error)
+	FindFollowers(ctx context.Context, userId int) ([]Follow, error)
+}
+
+// A friendship as requested by UserId to FriendId, mutual once accepted
+type Friendship struct {
+	UserId      int
+	FriendId    int
+	Status      string
+	RequestedAt time.Time
+	AcceptedAt  *time.Time //nil while pending
+}
+
+// A one-way follow, the followee does not have to agree
+type Follow struct {
+	FollowerId int
+	FolloweeId int
+	FollowedAt time.Time
+}
+
+// Other tells who the friendship is with, from the side of the user
+func (friendship Friendship) Other(userId int) int {
+	if friendship.UserId == userId {
+		return friendship.FriendId
+	}
+	return friendship.UserId
+}
+
+func (interactor *ProfileInteractor) RequestFriend(ctx context.Context, userId, friendId int) (Friendship, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RequestFriend")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return Friendship{}, err, code
+	}
+	if friendId == user.Id {
+		return Friendship{}, fmt.Errorf("Users cannot befriend themselves"), 400
+	}
+	friend, err, code := interactor.UserRepository.FindById(ctx, friendId)
+	if err != nil {
+		return Friendship{}, err, code
+	}
+
+	friendship := Friendship{UserId: user.Id, FriendId: friend.Id, Status: FriendshipPending,
+		RequestedAt: time.Now().UTC()}
+	err, code = interactor.FriendRepository.Request(ctx, friendship)
+	if err != nil {
+		return Friendship{}, err, code
+	}
+	interactor.Loggr.Info("Friend requested", "friendId", friend.Id)
+	return friendship, nil, 201
+}
+
+// AcceptFriend accepts the pending request the requester sent the user
+func (interactor *ProfileInteractor) AcceptFriend(ctx context.Context, userId, requesterId int) (Friendship, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "AcceptFriend")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return Friendship{}, err, code
+	}
+	acceptedAt := time.Now().UTC()
+	err, code = interactor.FriendRepository.Accept(ctx, user.Id, requesterId, acceptedAt)
+	if err != nil {
+		return Friendship{}, err, code
+	}
+	interactor.Loggr.Info("Friend accepted", "friendId", requesterId)
+	return Friendship{UserId: requesterId, FriendId: user.Id, Status: FriendshipAccepted,
+		AcceptedAt: &acceptedAt}, nil, 200
+}
+
+// RemoveFriend declines a request sent to the user, withdraws one the user
+// sent, or ends a friendship, whichever the two users have
+func (interactor *ProfileInteractor) RemoveFriend(ctx context.Context, userId, otherUserId int) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RemoveFriend")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return err, code
+	}
+	err, code = interactor.FriendRepository.Remove(ctx, user.Id, otherUserId)
+	if err != nil {
+		return err, code
+	}
+	interactor.Loggr.Info("Friendship removed", "friendId", otherUserId)
+	return nil, 200
+}
+
+// ShowFriends lists the friendships of a user. Pending requests are only
+// shown to the user themselves.
+func (interactor *ProfileInteractor) ShowFriends(ctx context.Context, viewerId, userId int) ([]Friendship, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowFriends")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err, code
+	}
+	err, code = interactor.checkUserVisible(ctx, viewerId, user)
+	if err != nil {
+		return nil, err, code
+	}
+	friendships, err := interactor.FriendRepository.FindByUser(ctx, user.Id)
+	if err != nil {
+		return nil, err, 500
+	}
+	if viewerId == user.Id {
+		return friendships, nil, 200
+	}
+	accepted := []Friendship{}
+	for _, friendship := range friendships {
+		if friendship.Status == FriendshipAccepted {
+			accepted = append(accepted, friendship)
+		}
+	}
+	return accepted, nil, 200
+}
+
+func (interactor *ProfileInteractor) FollowUser(ctx context.Context, userId, followeeId int) (Follow, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "FollowUser")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return Follow{}, err, code
+	}
+	if followeeId == user.Id {
+		return Follow{}, fmt.Errorf("Users cannot follow themselves"), 400
+	}
+	followee, err, code := interactor.UserRepository.FindById(ctx, followeeId)
+	if err != nil {
+		return Follow{}, err, code
+	}
+
+	follow := Follow{FollowerId: user.Id, FolloweeId: followee.Id, FollowedAt: time.Now().UTC()}
+	err = interactor.FriendRepository.Follow(ctx, follow)
+	if err != nil {
+		return Follow{}, err, 500
+	}
+	interactor.Loggr.Info("User followed", "followeeId", followee.Id)
+	return follow, nil, 200
+}
+
+func (interactor *ProfileInteractor) UnfollowUser(ctx context.Context, userId, followeeId int) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "UnfollowUser")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return err, code
+	}
+	err, code = interactor.FriendRepository.Unfollow(ctx, user.Id, followeeId)
+	if err != nil {
+		return err, code
+	}
+	interactor.Loggr.Info("User unfollowed", "followeeId", followeeId)
+	return nil, 200
+}
+
+// ShowFollows lists who the user follows, or who follows the user
+func (interactor *ProfileInteractor) ShowFollows(ctx context.Context, viewerId, userId int, followers bool) ([]Follow, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowFollows")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err, code
+	}
+	err, code = interactor.checkUserVisible(ctx, viewerId, user)
+	if err != nil {
+		return nil, err, code
+	}
+	var follows []Follow
+	if followers {
+		follows, err = interactor.FriendRepository.FindFollowers(ctx, user.Id)
+	} else {
+		follows, err = interactor.FriendRepository.FindFollowing(ctx, user.Id)
+	}
+	if err != nil {
+		return nil, err, 500
+	}
+	return follows, nil, 200
+}
+
+// ShowOwningFriends finds the friends of the user owning a catalog game, in
+// libraries they let their friends see
+func (interactor *ProfileInteractor) ShowOwningFriends(ctx context.Context, userId, gameId int) ([]User, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowOwningFriends")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err, code
+	}
+	game, err, code := interactor.GameRepository.FindById(ctx, gameId)
+	if err != nil {
+		return nil, err, code
+	}
+	friendIds, err := interactor.FriendRepository.FindOwningFriends(ctx, user.Id, game.Id)
+	if err != nil {
+		return nil, err, 500
+	}
+	friends := []User{}
+	for _, friendId := range friendIds {
+		friend, err, code := interactor.UserRepository.FindById(ctx, friendId)
+		if err != nil {
+			return nil, err, code
+		}
+		friends = append(friends, friend)
+	}
+	return friends, nil, 200
+}
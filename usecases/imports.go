@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MaxImportRows is the most games a single import can list
+const MaxImportRows = 5000
+
+// Outcome of importing one row into a library
+const (
+	ImportCreated   = "created"   //the game entered the catalog and the library
+	ImportLinked    = "linked"    //the catalog already knew the game, it joined the library at its catalog value
+	ImportDuplicate = "duplicate" //the game already existed in the library or earlier in the import
+	ImportInvalid   = "invalid"
+)
+
+type ImportRow struct {
+	Line     int
+	Name     string
+	Producer string
+	Value    float64
+	Problem  string //Set by the parser when the row could not be read
+}
+
+type ImportResult struct {
+	Line   int
+	Name   string
+	GameId int
+	Status string
+	Reason string
+}
+
+// ImportGames adds many games to a library of the user at once, matching them
+// against the catalog by name. Either every valid row is imported or none is.
+// A dry run reports what would happen without keeping anything.
+func (interactor *ProfileInteractor) ImportGames(ctx context.Context, userId, libraryId int, rows []ImportRow, dryRun bool) ([]ImportResult, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ImportGames")
+	defer end()
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
+	if err != nil {
+		return nil, err, code
+	}
+	err, code = checkLibraryOwner(userId, library, "import games to")
+	if err != nil {
+		return nil, err, code
+	}
+	if len(rows) == 0 {
+		err := fmt.Errorf("Nothing to import")
+		return nil, err, 400
+	}
+	if len(rows) > MaxImportRows {
+		err := fmt.Errorf("Cannot import more than %d games at once", MaxImportRows)
+		return nil, err, 400
+	}
+
+	results := make([]ImportResult, len(rows))
+	var valid []ImportRow
+	var validIndexes []int
+	seen := make(map[string]int)
+	for i, row := range rows {
+		row.Name = strings.TrimSpace(row.Name)
+		row.Producer = strings.TrimSpace(row.Producer)
+		results[i] = ImportResult{Line: row.Line, Name: row.Name}
+		firstLine, repeated := seen[row.Name]
+		switch {
+		case row.Problem != "":
+			results[i].Status, results[i].Reason = ImportInvalid, row.Problem
+		case row.Name == "":
+			results[i].Status, results[i].Reason = ImportInvalid, "Name is required"
+		case math.IsNaN(row.Value) || math.IsInf(row.Value, 0):
+			results[i].Status, results[i].Reason = ImportInvalid, "Value must be a finite number"
+		case row.Value < 0:
+			results[i].Status, results[i].Reason = ImportInvalid, "Value cannot be negative"
+		case repeated:
+			results[i].Status = ImportDuplicate
+			results[i].Reason = fmt.Sprintf("Same game as line %d", firstLine)
+		default:
+			seen[row.Name] = row.Line
+			valid = append(valid, row)
+			validIndexes = append(validIndexes, i)
+		}
+	}
+
+	if len(valid) > 0 {
+		imported, err := interactor.GameRepository.Import(ctx, library.Id, valid, dryRun)
+		if err != nil {
+			return nil, err, 500
+		}
+		for i, result := range imported {
+			results[validIndexes[i]] = result
+		}
+	}
+
+	if !dryRun {
+		for _, result := range results {
+			if result.Status != ImportCreated && result.Status != ImportLinked {
+				continue
+			}
+			err = interactor.unwish(ctx, userId, result.GameId)
+			if err != nil {
+				return nil, err, 500
+			}
+		}
+		interactor.Loggr.Info("Games imported", "libraryId", library.Id, "rows", len(rows))
+	}
+	return results, nil, 200
+}
@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"game-tracker/domain"
+)
+
+type PlayerRepository interface {
+	domain.PlayerRepository
+	Rename(ctx context.Context, player domain.Player) (error, int)
+	Merge(ctx context.Context, playerId, otherPlayerId int) error
+	Link(ctx context.Context, playerId int, user User) error
+}
+
+// ShowPlayer finds the player with every account (user) they own. Viewers
+// outside of the player only get the accounts and libraries they can see.
+func (interactor *ProfileInteractor) ShowPlayer(ctx context.Context, viewerId, playerId int) (domain.Player, []User, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowPlayer")
+	defer end()
+	player, err, code := interactor.PlayerRepository.FindById(ctx, playerId)
+	if err != nil {
+		err = fmt.Errorf("Player #%d does not exist", playerId)
+		return domain.Player{}, nil, err, code
+	}
+	userIds, err := interactor.UserRepository.FindByPlayer(ctx, player.Id)
+	if err != nil {
+		return domain.Player{}, nil, err, 500
+	}
+	users := []User{}
+	for _, userId := range userIds {
+		user, err, code := interactor.UserRepository.FindById(ctx, userId)
+		if err != nil {
+			return domain.Player{}, nil, err, code
+		}
+		users = append(users, user)
+	}
+	for _, user := range users {
+		if user.Id == viewerId {
+			return player, users, nil, 200
+		}
+	}
+
+	visibleUsers := []User{}
+	for _, user := range users {
+		visible, err := interactor.canSee(ctx, viewerId, user.Id, user.Visibility)
+		if err != nil {
+			return domain.Player{}, nil, err, 500
+		}
+		if !visible {
+			continue
+		}
+		user.LibraryIds, err = interactor.visibleLibraryIds(ctx, viewerId, user)
+		if err != nil {
+			return domain.Player{}, nil, err, 500
+		}
+		visibleUsers = append(visibleUsers, user)
+	}
+	return player, visibleUsers, nil, 200
+}
+
+// RenamePlayer renames the player of the user, player names cannot repeat
+func (interactor *ProfileInteractor) RenamePlayer(ctx context.Context, userId, playerId int, name string) (domain.Player, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RenamePlayer")
+	defer end()
+	user, err, code := interactor.playerUser(ctx, userId, playerId)
+	if err != nil {
+		return domain.Player{}, err, code
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		err = fmt.Errorf("Player name cannot be empty")
+		return domain.Player{}, err, 400
+	}
+
+	player := domain.Player{Id: user.Player.Id, Name: name}
+	err, code = interactor.PlayerRepository.Rename(ctx, player)
+	if err != nil {
+		return domain.Player{}, err, code
+	}
+	interactor.Loggr.Info("Player renamed", "playerId", player.Id, "name", player.Name)
+	return player, nil, 200
+}
+
+// MergePlayers moves every account and achievement of another player into
+// the player of the user, then removes the other player. The user proves the
+// other player is theirs with the credentials of one of its accounts.
+func (interactor *ProfileInteractor) MergePlayers(ctx context.Context, userId, playerId int, username, password string) (domain.Player, []User, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "MergePlayers")
+	defer end()
+	user, err, code := interactor.playerUser(ctx, userId, playerId)
+	if err != nil {
+		return domain.Player{}, nil, err, code
+	}
+	other, err, code := interactor.credentialsUser(ctx, username, password)
+	if err != nil {
+		return domain.Player{}, nil, err, code
+	}
+	if other.Player.Id == user.Player.Id {
+		err = fmt.Errorf("User #%d already belongs to player #%d", other.Id, playerId)
+		return domain.Player{}, nil, err, 400
+	}
+
+	err = interactor.PlayerRepository.Merge(ctx, playerId, other.Player.Id)
+	if err != nil {
+		return domain.Player{}, nil, err, 500
+	}
+	interactor.Loggr.Info("Players merged", "playerId", playerId,
+		"mergedPlayerId", other.Player.Id)
+	return interactor.ShowPlayer(ctx, userId, playerId)
+}
+
+// playerUser finds the user acting on a player, who must be one of its accounts
+func (interactor *ProfileInteractor) playerUser(ctx context.Context, userId, playerId int) (User, error, int) {
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return User{}, err, code
+	}
+	if user.Player.Id != playerId {
+		err = fmt.Errorf("User #%d does not belong to player #%d", userId, playerId)
+		return User{}, err, 403
+	}
+	return user, nil, 200
+}
+
+// credentialsUser finds the user the credentials log in as
+func (interactor *ProfileInteractor) credentialsUser(ctx context.Context, username, password string) (User, error, int) {
+	id, err, code := interactor.FindLoginId(ctx, username, password)
+	if err != nil {
+		return User{}, err, code
+	}
+	return interactor.UserRepository.FindById(ctx, id)
+}
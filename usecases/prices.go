@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Where a recorded game value came from
+const (
+	PriceSourceCatalog = "catalog" //first value of a game entering the catalog
+	PriceSourceUser    = "user"    //a user updated the catalog value directly
+)
+
+type GamePrice struct {
+	GameId     int
+	Value      float64
+	Source     string
+	RecordedAt time.Time
+}
+
+// ShowPrices returns the value history of a catalog game between from and to.
+// A zero from or to leaves that end of the range open.
+func (interactor *ProfileInteractor) ShowPrices(ctx context.Context, gameId int, from, to time.Time) (Game, []GamePrice, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowPrices")
+	defer end()
+	game, err, code := interactor.GameRepository.FindById(ctx, gameId)
+	if err != nil {
+		return Game{}, nil, err, code
+	}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if to.Before(from) {
+		err := fmt.Errorf("End of the date range is before its start")
+		return Game{}, nil, err, 400
+	}
+	game.LowestValue, err = interactor.GameRepository.LowestPrice(ctx, gameId)
+	if err != nil {
+		return Game{}, nil, err, 500
+	}
+	prices, err := interactor.GameRepository.FindPrices(ctx, gameId, from, to)
+	if err != nil {
+		return Game{}, nil, err, 500
+	}
+	return game, prices, nil, 200
+}
+
+// setGameValue changes the catalog value of a game, keeps its history and
+// notifies users waiting for a price drop.
+func (interactor *ProfileInteractor) setGameValue(ctx context.Context, game Game, value float64, source string) (Game, error) {
+	oldValue := game.Value
+	if value == oldValue {
+		return game, nil
+	}
+	game.Value = value
+	err := interactor.GameRepository.UpdateValue(ctx, game)
+	if err != nil {
+		return game, err
+	}
+	game.Version++
+	err = interactor.gameValueChanged(ctx, game, oldValue, source)
+	return game, err
+}
+
+// gameValueChanged must follow every stored change of a game value.
+func (interactor *ProfileInteractor) gameValueChanged(ctx context.Context, game Game, oldValue float64, source string) error {
+	err := interactor.recordPrice(ctx, game, source)
+	if err != nil {
+		return err
+	}
+	return interactor.notifyPriceDrop(ctx, game, oldValue)
+}
+
+func (interactor *ProfileInteractor) recordPrice(ctx context.Context, game Game, source string) error {
+	price := GamePrice{GameId: game.Id, Value: game.Value, Source: source,
+		RecordedAt: time.Now().UTC()}
+	return interactor.GameRepository.RecordPrice(ctx, price)
+}
@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Everything held about a user and their player
+type PersonalData struct {
+	User          User
+	AccountIds    []int //Every user of the same player, this one included
+	Libraries     []Library
+	Games         []LibraryEntry
+	Wishlist      []WishlistEntry
+	Notifications []Notification
+	Achievements  []PlayerAchievement
+	Friendships   []Friendship //Pending ones too, sent and received
+	Following     []Follow
+	Followers     []Follow
+	Events        []Event
+	Shares        []Share
+	AuditEntries  []AuditEntry //What the user did, as the audit log recorded it
+}
+
+// The tombstone left by an erased user
+type Erasure struct {
+	Id           int
+	UserId       int
+	PlayerId     int
+	UserNameHash string
+	PlayerErased bool //The player goes along with its last user
+	ErasedAt     time.Time
+}
+
+func (interactor *ProfileInteractor) ExportPersonalData(ctx context.Context, userId int) (PersonalData, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ExportPersonalData")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return PersonalData{}, err, code
+	}
+	data := PersonalData{User: user}
+
+	data.AccountIds, err = interactor.UserRepository.FindByPlayer(ctx, user.Player.Id)
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	for _, libraryId := range user.LibraryIds {
+		library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
+		if err != nil {
+			return PersonalData{}, err, code
+		}
+		data.Libraries = append(data.Libraries, library)
+	}
+	err = interactor.GameRepository.EachInLibraries(ctx, user.Id, 0, func(entry LibraryEntry) error {
+		data.Games = append(data.Games, entry)
+		return nil
+	})
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	data.Wishlist, err = interactor.WishlistRepository.FindByUser(ctx, user.Id)
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	data.Notifications, err = interactor.WishlistRepository.FindNotifications(ctx, user.Id)
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	data.Achievements, err = interactor.AchievementRepository.FindAllUnlocked(ctx, user.Player.Id)
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	data.Friendships, err = interactor.FriendRepository.FindByUser(ctx, user.Id)
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	data.Following, err = interactor.FriendRepository.FindFollowing(ctx, user.Id)
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	data.Followers, err = interactor.FriendRepository.FindFollowers(ctx, user.Id)
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	data.Events, err = interactor.EventRepository.FindByUser(ctx, user.Id, user.Id, 0, 0)
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	for _, library := range data.Libraries {
+		shares, err := interactor.ShareRepository.FindByLibrary(ctx, library.Id)
+		if err != nil {
+			return PersonalData{}, err, 500
+		}
+		data.Shares = append(data.Shares, shares...)
+	}
+	filter := AuditFilter{ActorId: user.Id, To: time.Now().UTC()}
+	data.AuditEntries, err = interactor.AuditRepository.Find(ctx, filter)
+	if err != nil {
+		return PersonalData{}, err, 500
+	}
+	interactor.Loggr.Info("Personal data exported")
+	return data, nil, 200
+}
+
+// EraseUser removes the user with their login, info, libraries and their
+// share links, wishlist, inbox, friendships, follows and activity, and their
+// player when no other user belongs to it. Catalog games are shared and stay,
+// so do the entries of the append-only audit log. A tombstone records the
+// erasure. Removed users waiting for their purge can be erased too.
+func (interactor *ProfileInteractor) EraseUser(ctx context.Context, userId int) (Erasure, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "EraseUser")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if code == 404 {
+		user, _, err, code = interactor.UserRepository.FindDeleted(ctx, userId)
+	}
+	if err != nil {
+		return Erasure{}, err, code
+	}
+
+	nameHash := sha256.Sum256([]byte(user.Name))
+	erasure := Erasure{UserId: user.Id, PlayerId: user.Player.Id,
+		UserNameHash: hex.EncodeToString(nameHash[:]), ErasedAt: time.Now().UTC()}
+	erasure, err = interactor.UserRepository.Erase(ctx, user, erasure)
+	if err != nil {
+		return Erasure{}, err, 500
+	}
+	detail := fmt.Sprintf("erasure #%d", erasure.Id)
+	err = interactor.audit(ctx, AuditUserErased, user.Id, "user", user.Id, detail)
+	if err != nil {
+		return Erasure{}, err, 500
+	}
+	interactor.Loggr.Info("User erased", "erasureId", erasure.Id)
+	return erasure, nil, 200
+}
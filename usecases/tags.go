@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const maxTagLength = 32
+
+type TagRepository interface {
+	Store(ctx context.Context, libraryId, gameId int, tag string) error
+	Remove(ctx context.Context, libraryId, gameId int, tag string) error
+	FindTags(ctx context.Context, libraryId, gameId int) ([]string, error)
+	FindUserTags(ctx context.Context, userId int, prefix string) ([]string, error)
+	FindUserGames(ctx context.Context, userId int, tag string) ([]LibraryEntry, error)
+}
+
+// A game as found in one of the libraries of a user
+type LibraryEntry struct {
+	LibraryId int
+	Game      Game
+}
+
+func (interactor *ProfileInteractor) TagGame(ctx context.Context, userId, libraryId, gameId int, tag string) ([]string, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "TagGame")
+	defer end()
+	library, err, code := interactor.ownedLibraryWithGame(ctx, userId, libraryId, gameId)
+	if err != nil {
+		return nil, err, code
+	}
+	tag, err = normalizeTag(tag)
+	if err != nil {
+		return nil, err, 400
+	}
+
+	tags, err := interactor.TagRepository.FindTags(ctx, library.Id, gameId)
+	if err != nil {
+		return nil, err, 500
+	}
+	for _, existing := range tags {
+		if existing == tag {
+			err := fmt.Errorf("Game #%d is already tagged '%s'", gameId, tag)
+			return nil, err, 400
+		}
+	}
+	err = interactor.TagRepository.Store(ctx, library.Id, gameId, tag)
+	if err != nil {
+		return nil, err, 500
+	}
+	interactor.Loggr.Info("Game tagged", "gameId", gameId, "libraryId", library.Id, "tag", tag)
+	return append(tags, tag), nil, 201
+}
+
+func (interactor *ProfileInteractor) UntagGame(ctx context.Context, userId, libraryId, gameId int, tag string) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "UntagGame")
+	defer end()
+	library, err, code := interactor.ownedLibraryWithGame(ctx, userId, libraryId, gameId)
+	if err != nil {
+		return err, code
+	}
+	tag, err = normalizeTag(tag)
+	if err != nil {
+		return err, 400
+	}
+	err = interactor.TagRepository.Remove(ctx, library.Id, gameId, tag)
+	if err != nil {
+		return err, 500
+	}
+	return nil, 200
+}
+
+// ShowUserGames lists the games of every library of the user, restricted to
+// the games carrying the given tag unless it is empty.
+func (interactor *ProfileInteractor) ShowUserGames(ctx context.Context, userId int, tag string) ([]LibraryEntry, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowUserGames")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err, code
+	}
+	if tag != "" {
+		tag, err = normalizeTag(tag)
+		if err != nil {
+			return nil, err, 400
+		}
+	}
+	entries, err := interactor.TagRepository.FindUserGames(ctx, user.Id, tag)
+	if err != nil {
+		return nil, err, 500
+	}
+	return entries, nil, 200
+}
+
+// SuggestTags autocompletes a tag from the tags the user has already used.
+func (interactor *ProfileInteractor) SuggestTags(ctx context.Context, userId int, prefix string) ([]string, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "SuggestTags")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err, code
+	}
+	tags, err := interactor.TagRepository.FindUserTags(ctx, user.Id, strings.ToLower(strings.TrimSpace(prefix)))
+	if err != nil {
+		return nil, err, 500
+	}
+	return tags, nil, 200
+}
+
+func (interactor *ProfileInteractor) ownedLibraryWithGame(ctx context.Context, userId, libraryId, gameId int) (Library, error, int) {
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
+	if err != nil {
+		return Library{}, err, code
+	}
+	err, code = checkLibraryOwner(userId, library, "tag games in")
+	if err != nil {
+		return Library{}, err, code
+	}
+	for _, id := range library.GameIds {
+		if id == gameId {
+			return library, nil, 200
+		}
+	}
+	err = fmt.Errorf("Game #%d is not in library #%d", gameId, library.Id)
+	return Library{}, err, 404
+}
+
+// Application rule: tags are case insensitive single labels
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("Tag cannot be empty")
+	}
+	if len(tag) > maxTagLength {
+		return "", fmt.Errorf("Tag cannot be longer than %d characters", maxTagLength)
+	}
+	return tag, nil
+}
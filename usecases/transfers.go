@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+)
+
+// Outcome of moving or copying one game between libraries
+const (
+	TransferMoved    = "moved"
+	TransferCopied   = "copied"
+	TransferConflict = "conflict" //the game already existed in the target library
+	TransferMissing  = "missing"  //the game was not in the source library
+)
+
+type Transfer struct {
+	GameId int
+	Status string
+}
+
+// TransferGames moves games from one library of the user to another, or copies
+// them when keep is set. Games already in the target are reported as conflicts
+// and left alone, the others are transferred together or not at all.
+func (interactor *ProfileInteractor) TransferGames(ctx context.Context, userId, libraryId, targetLibraryId int, gameIds []int, keep bool) ([]Transfer, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "TransferGames")
+	defer end()
+	if len(gameIds) == 0 {
+		err := fmt.Errorf("No game to transfer")
+		return nil, err, 400
+	}
+	if libraryId == targetLibraryId {
+		err := fmt.Errorf("Source and target libraries are the same")
+		return nil, err, 400
+	}
+	for _, id := range []int{libraryId, targetLibraryId} {
+		library, err, code := interactor.LibraryRepository.FindById(ctx, id)
+		if err != nil {
+			return nil, err, code
+		}
+		err, code = checkLibraryOwner(userId, library, "transfer games of")
+		if err != nil {
+			return nil, err, code
+		}
+	}
+
+	seen := make(map[int]bool)
+	var uniqueIds []int
+	for _, gameId := range gameIds {
+		if !seen[gameId] {
+			seen[gameId] = true
+			uniqueIds = append(uniqueIds, gameId)
+		}
+	}
+	transfers, err := interactor.GameRepository.Transfer(ctx, uniqueIds, libraryId, targetLibraryId, keep)
+	if err != nil {
+		return nil, err, 500
+	}
+	interactor.Loggr.Info("Games transferred", "libraryId", libraryId,
+		"targetLibraryId", targetLibraryId, "games", len(uniqueIds))
+	return transfers, nil, 200
+}
@@ -1,35 +1,56 @@
 package usecases
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"game-tracker/domain"
 )
 
 type UserRepository interface {
-	Store(user User) (int, error)
-	Remove(user User) error
-	FindById(id int) (User, error, int)
-	UserExisted(userName string) (bool, error)
-	StoreInfo(user User, info string) error
-	LoadInfo(user User) (string, error)
-	PlayerNameMatchesId(user User) (bool, error)
-	FindLoginId(username, password string) (int, bool, error)
-	AddLoginInfo(username, password string) error
-	RemoveLoginInfo(user User) error
+	Store(ctx context.Context, user User, password string) (int, error)
+	Remove(ctx context.Context, user User, deletedAt time.Time) error
+	FindById(ctx context.Context, id int) (User, error, int)
+	FindDeleted(ctx context.Context, id int) (User, time.Time, error, int)
+	Restore(ctx context.Context, user User, deletedAt time.Time) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
+	UserExisted(ctx context.Context, userName string) (bool, error)
+	StoreInfo(ctx context.Context, user User, info string) error
+	LoadInfo(ctx context.Context, user User) (string, error)
+	PlayerNameMatchesId(ctx context.Context, user User) (bool, error)
+	FindLoginId(ctx context.Context, username, password string) (int, bool, error)
+	RemoveLoginInfo(ctx context.Context, user User) error
+	Update(ctx context.Context, user User, oldName string) (error, int)
+	FindByPlayer(ctx context.Context, playerId int) ([]int, error)
+	Erase(ctx context.Context, user User, erasure Erasure) (Erasure, error)
 }
 
 type LibraryRepository interface {
-	Store(library Library) (int, error)
-	Remove(library Library) error
-	FindById(id int) (Library, error, int)
+	Store(ctx context.Context, library Library) (int, error)
+	Remove(ctx context.Context, library Library, deletedAt time.Time) error
+	FindById(ctx context.Context, id int) (Library, error, int)
+	FindDeleted(ctx context.Context, id int) (Library, time.Time, error, int)
+	Restore(ctx context.Context, library Library) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
+	Update(ctx context.Context, library Library) (error, int)
 }
 
 type GameRepository interface {
-	Store(game Game) (int, error)
-	AddToLib(gameId, libraryId int) (error, int)
-	RemoveFromLib(game Game, libraryId int) error
-	FindById(id int) (Game, error, int)
+	Store(ctx context.Context, game Game) (int, bool, error)
+	AddToLib(ctx context.Context, gameId, libraryId int) (error, int)
+	RemoveFromLib(ctx context.Context, game Game, libraryId int) error
+	FindById(ctx context.Context, id int) (Game, error, int)
+	InUserLibraries(ctx context.Context, gameId, userId int) (bool, error)
+	UpdateValue(ctx context.Context, game Game) error
+	Update(ctx context.Context, game Game) (error, int)
+	FindIdByName(ctx context.Context, name string) (int, bool, error)
+	Transfer(ctx context.Context, gameIds []int, fromLibraryId, toLibraryId int, keep bool) ([]Transfer, error)
+	Import(ctx context.Context, libraryId int, rows []ImportRow, dryRun bool) ([]ImportResult, error)
+	EachInLibraries(ctx context.Context, userId, libraryId int, fn func(entry LibraryEntry) error) error
+	RecordPrice(ctx context.Context, price GamePrice) error
+	FindPrices(ctx context.Context, gameId int, from, to time.Time) ([]GamePrice, error)
+	LowestPrice(ctx context.Context, gameId int) (float64, error)
 }
 
 type User struct {
@@ -38,315 +59,405 @@ type User struct {
 	Player       domain.Player //This user (account) was created by some player
 	PersonalInfo string
 	LibraryIds   []int
+	Visibility   string //Who besides the user sees their profile
+	Admin        bool   //Admins read the audit log
+	Version      int    //Incremented on every update, guards against lost updates
 }
 
 type Library struct {
-	Id      int
-	User    User //This library belongs to some user
-	GameIds []int
+	Id          int
+	User        User //This library belongs to some user
+	Name        string
+	Description string
+	GameIds     []int
+	Visibility  string //Who besides its owner sees the library
+	Version     int
 }
 
 type Game struct {
-	Id       int
-	Name     string
-	Producer string
-	Value    float64
+	Id          int
+	Name        string
+	Producer    string
+	Value       float64
+	LowestValue float64  //Lowest value ever recorded, only filled when shown
+	Tags        []string //Tags of the library the game is shown from
+	Version     int
 }
 
+// LoggerRepository writes leveled log lines. Fields are key and value pairs,
+// With gives a logger adding its fields to every line.
 type LoggerRepository interface {
-	Log(message string) error
+	Debug(message string, fields ...interface{})
+	Info(message string, fields ...interface{})
+	Warn(message string, fields ...interface{})
+	Error(message string, fields ...interface{})
+	With(fields ...interface{}) LoggerRepository
+}
+
+// MetricsRepository counts what the use cases do, by action
+type MetricsRepository interface {
+	Count(action string)
+}
+
+// TracerRepository starts a span named after a use case, ended by calling the
+// returned function. The returned context carries the span to the
+// repositories.
+type TracerRepository interface {
+	Start(ctx context.Context, name string) (context.Context, func())
 }
 
 type ProfileInteractor struct {
-	UserRepository    UserRepository
-	LibraryRepository LibraryRepository
-	GameRepository    GameRepository
-	Loggr             LoggerRepository
+	UserRepository        UserRepository
+	PlayerRepository      PlayerRepository
+	LibraryRepository     LibraryRepository
+	GameRepository        GameRepository
+	AchievementRepository AchievementRepository
+	WishlistRepository    WishlistRepository
+	TagRepository         TagRepository
+	ShareRepository       ShareRepository
+	FriendRepository      FriendRepository
+	EventRepository       EventRepository
+	AuditRepository       AuditRepository
+	Loggr                 LoggerRepository
+	Metrics               MetricsRepository
+	Tracer                TracerRepository
+	Retention             time.Duration //How long removed users and libraries can be restored
+	Origin                string        //Address of the request acted on, for the audit log
 }
 
-func (interactor *ProfileInteractor) AddUser(player domain.Player, userName, password string) (int, error, int) {
+func (interactor *ProfileInteractor) AddUser(ctx context.Context, player domain.Player, userName, password string) (User, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "AddUser")
+	defer end()
 	// Application rule: usernames cannot repeat
-	existed, err := interactor.UserRepository.UserExisted(userName)
+	existed, err := interactor.UserRepository.UserExisted(ctx, userName)
 	if err != nil {
-		return 0, err, 500
+		return User{}, err, 500
 	}
 	if existed {
 		err := fmt.Errorf("Username '%s' is taken", userName)
-		// interactor.Logger.Log(err.Error())
-		return 0, err, 400
+		return User{}, err, 400
 	}
 
 	user := User{Name: userName, Player: player, PersonalInfo: ""}
 
-	match, err := interactor.UserRepository.PlayerNameMatchesId(user)
+	match, err := interactor.UserRepository.PlayerNameMatchesId(ctx, user)
 	if err != nil {
-		return 0, err, 500
+		return User{}, err, 500
 	}
 	if !match {
 		err = fmt.Errorf("Player name does not match player Id")
-		return 0, err, 400
+		return User{}, err, 400
 	}
 
-	id, err := interactor.UserRepository.Store(user)
+	// The player id stored is the real one of the player name
+	id, err := interactor.UserRepository.Store(ctx, user, password)
 	if err != nil {
-		// interactor.Logger.Log(err.Error())
-		return 0, err, 500
+		return User{}, err, 500
 	}
-	err = interactor.UserRepository.AddLoginInfo(userName, password)
+	user, err, code := interactor.UserRepository.FindById(ctx, id)
 	if err != nil {
-		return 0, err, 500
+		return User{}, err, code
+	}
+	err = interactor.audit(ctx, AuditUserCreated, user.Id, "user", user.Id, "")
+	if err != nil {
+		return User{}, err, 500
 	}
 
-	fmt.Printf("Added user #%d for player #%d\n", id, player.Id)
-	return id, nil, 201
+	interactor.Loggr.Info("User added", "addedUserId", id, "playerId", user.Player.Id)
+	return user, nil, 201
 }
 
-func (interactor *ProfileInteractor) ShowUser(userId int) (string, []int, error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+// ShowUser shows the user to the viewer with only the libraries the viewer
+// can see. A viewerId of 0 is an anonymous viewer.
+func (interactor *ProfileInteractor) ShowUser(ctx context.Context, viewerId, userId int) (User, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowUser")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
 	if err != nil {
 		err = fmt.Errorf(fmt.Sprintf("User #%d does not exist", userId))
-		return "", nil, err, code
+		return User{}, err, code
 	}
-	var libraryIds []int
-	for _, libraryId := range user.LibraryIds {
-		libraryIds = append(libraryIds, libraryId)
+	err, code = interactor.checkUserVisible(ctx, viewerId, user)
+	if err != nil {
+		return User{}, err, code
+	}
+	user.LibraryIds, err = interactor.visibleLibraryIds(ctx, viewerId, user)
+	if err != nil {
+		return User{}, err, 500
 	}
-	return user.Name, libraryIds, nil, 200
+	return user, nil, 200
 }
 
-func (interactor *ProfileInteractor) RemoveUser(userId int) (error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+func (interactor *ProfileInteractor) RemoveUser(ctx context.Context, userId int) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RemoveUser")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
 	if err != nil {
-		// interactor.Logger.Log(err.Error())
 		err = fmt.Errorf(fmt.Sprintf("User #%d does not exist", userId))
 		return err, code
 	}
 
-	for _, libraryId := range user.LibraryIds {
-		err, code = interactor.RemoveLibrary(userId, libraryId)
-		if err != nil {
-			return err, code
-		}
-	}
-	err = interactor.UserRepository.Remove(user)
+	// The user keeps their login until purged, to be able to restore
+	err = interactor.UserRepository.Remove(ctx, user, time.Now().UTC())
 	if err != nil {
 		return err, 500
 	}
-	err = interactor.UserRepository.RemoveLoginInfo(user)
+	err = interactor.audit(ctx, AuditUserRemoved, user.Id, "user", user.Id, "")
 	if err != nil {
 		return err, 500
 	}
-	// interactor.Logger.Log(fmt.Sprintf("Removed user #%s (id #%d)", user.Name, user.Id))
-	fmt.Printf("Deleted user #%d\n", userId)
+	interactor.Loggr.Info("User removed")
 	return nil, 200
 }
 
-func (interactor *ProfileInteractor) ShowUserInfo(userId int) (string, error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+func (interactor *ProfileInteractor) ShowUserInfo(ctx context.Context, viewerId, userId int) (string, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowUserInfo")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
 	if err != nil {
 		err = fmt.Errorf(fmt.Sprintf("User #%d does not exist", userId))
 		return "", err, code
 	}
-	info, err := interactor.UserRepository.LoadInfo(user)
+	err, code = interactor.checkUserVisible(ctx, viewerId, user)
+	if err != nil {
+		return "", err, code
+	}
+	info, err := interactor.UserRepository.LoadInfo(ctx, user)
 	if err != nil {
 		return "", err, 500
 	}
-	fmt.Println(fmt.Sprintf("Printed information of user #%d", user.Id))
+	interactor.Loggr.Debug("User information shown", "shownUserId", user.Id)
 	return info, nil, 200
 }
 
-func (interactor *ProfileInteractor) EditUserInfo(userId int, info string) (error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+func (interactor *ProfileInteractor) EditUserInfo(ctx context.Context, userId int, info string) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "EditUserInfo")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
 	if err != nil {
 		return err, code
 	}
-	err = interactor.UserRepository.StoreInfo(user, info)
+	err = interactor.UserRepository.StoreInfo(ctx, user, info)
 	if err != nil {
 		return err, 500
 	}
-	fmt.Println(fmt.Sprintf("Editted information of user '%s' (id #%d)", user.Name, user.Id))
+	interactor.Loggr.Info("User information edited")
 	return nil, 200
 }
 
-func (interactor *ProfileInteractor) AddLibrary(userId int) (int, error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+func (interactor *ProfileInteractor) AddLibrary(ctx context.Context, userId int, name, description, visibility string) (int, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "AddLibrary")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
 	if err != nil {
 		return 0, err, code
 	}
+	// Libraries are private unless told otherwise
+	if visibility == "" {
+		visibility = VisibilityPrivate
+	}
+	err = checkVisibility(visibility)
+	if err != nil {
+		return 0, err, 400
+	}
 
-	library := Library{User: user, GameIds: []int{}}
-	id, err := interactor.LibraryRepository.Store(library)
+	library := Library{User: user, Name: name, Description: description, GameIds: []int{},
+		Visibility: visibility}
+	id, err := interactor.LibraryRepository.Store(ctx, library)
 	if err != nil {
 		return 0, err, 500
 	}
-	fmt.Printf("User #%d added library #%d\n", user.Id, id)
+	interactor.Loggr.Info("Library added", "libraryId", id)
 	return id, nil, 200
 }
 
-func (interactor *ProfileInteractor) ShowLibrary(userId, libraryId int) ([]int, error, int) {
-	var gameIds []int
-	library, err, code := interactor.LibraryRepository.FindById(libraryId)
+// ShowLibrary shows a library of the user to the viewer, as far as its
+// visibility allows. A viewerId of 0 is an anonymous viewer.
+func (interactor *ProfileInteractor) ShowLibrary(ctx context.Context, viewerId, userId, libraryId int) (Library, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowLibrary")
+	defer end()
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
+	if err == nil && library.User.Id != userId {
+		err, code = fmt.Errorf("Library is not one of the user"), 404
+	}
 	if err != nil {
 		err = fmt.Errorf(fmt.Sprintf("Library #%d of user #%d does not exist", libraryId, userId))
-		return nil, err, code
+		return Library{}, err, code
 	}
 
-	if userId != library.User.Id {
-		message := "User #%d is not allowed to see library #%d of user #%d"
-		err := fmt.Errorf(message, userId, libraryId, library.User.Id)
-		return nil, err, 403
-	} else {
-		for _, gameId := range library.GameIds {
-			gameIds = append(gameIds, gameId)
-		}
-		return gameIds, nil, 200
+	err, code = interactor.checkLibraryVisible(ctx, viewerId, library)
+	if err != nil {
+		return Library{}, err, code
 	}
+	return library, nil, 200
 }
 
-func (interactor *ProfileInteractor) RemoveLibrary(userId, libraryId int) (error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+func (interactor *ProfileInteractor) RemoveLibrary(ctx context.Context, userId, libraryId int) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RemoveLibrary")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
 	if err != nil {
 		return err, code
 	}
-	library, err, code := interactor.LibraryRepository.FindById(libraryId)
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
 	if err != nil {
 		return err, code
 	}
-	if userId != library.User.Id {
-		err := fmt.Errorf("User #%d cannot remove library of user #%d",
-			userId, library.User.Id)
-		return err, 403
+	err, code = checkLibraryOwner(user.Id, library, "remove")
+	if err != nil {
+		return err, code
 	}
 
-	for _, gameId := range library.GameIds {
-		game, err, code := interactor.GameRepository.FindById(gameId)
-		if err != nil {
-			return err, code
-		}
-		err = interactor.GameRepository.RemoveFromLib(game, libraryId)
-		if err != nil {
-			return err, 500
-		}
+	// The games stay listed in the library until it is purged
+	err = interactor.LibraryRepository.Remove(ctx, library, time.Now().UTC())
+	if err != nil {
+		return err, 500
 	}
-	err = interactor.LibraryRepository.Remove(library)
+	err = interactor.audit(ctx, AuditLibraryRemoved, user.Id, "library", library.Id, "")
 	if err != nil {
 		return err, 500
 	}
-	fmt.Printf("User #%d removed library #%d\n", user.Id, library.Id)
+	interactor.Loggr.Info("Library removed", "libraryId", library.Id)
 	return nil, 200
 }
 
-func (interactor *ProfileInteractor) ShowGame(userId, libraryId, gameId int) (Game, error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+func (interactor *ProfileInteractor) ShowGame(ctx context.Context, viewerId, userId, libraryId, gameId int) (Game, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowGame")
+	defer end()
+	_, err, code := interactor.ShowLibrary(ctx, viewerId, userId, libraryId)
 	if err != nil {
 		return Game{}, err, code
 	}
-	library, err, code := interactor.LibraryRepository.FindById(libraryId)
+
+	game, err, code := interactor.GameRepository.FindById(ctx, gameId)
 	if err != nil {
 		return Game{}, err, code
 	}
-	if user.Id != library.User.Id {
-		message := "User #%d is not allowed to see games in library #%d of user #%d"
-		err := fmt.Errorf(message, user.Id, library.Id, library.User.Id)
-		return Game{}, err, 403
+	game.LowestValue, err = interactor.GameRepository.LowestPrice(ctx, gameId)
+	if err != nil {
+		return Game{}, err, 500
 	}
-
-	game, err, code := interactor.GameRepository.FindById(gameId)
+	game.Tags, err = interactor.TagRepository.FindTags(ctx, libraryId, gameId)
 	if err != nil {
-		return Game{}, err, code
+		return Game{}, err, 500
 	}
 	return game, nil, 200
 }
 
-func (interactor *ProfileInteractor) AddGame(userId, libraryId int, gameName, gameProducer string, gameValue float64) (int, error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+func (interactor *ProfileInteractor) AddGame(ctx context.Context, userId, libraryId int, gameName, gameProducer string, gameValue float64) (int, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "AddGame")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
 	if err != nil {
 		return 0, err, code
 	}
-	library, err, code := interactor.LibraryRepository.FindById(libraryId)
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
 	if err != nil {
 		return 0, err, code
 	}
-	if user.Id != library.User.Id {
-		message := "User #%d is not allowed to add games to library #%d of user #%d"
-		err := fmt.Errorf(message, user.Id, library.Id, library.User.Id)
-		return 0, err, 403
+	err, code = checkLibraryOwner(user.Id, library, "add games to")
+	if err != nil {
+		return 0, err, code
 	}
 
 	game := Game{Name: gameName, Producer: gameProducer, Value: gameValue}
-	id, err := interactor.GameRepository.Store(game)
+	id, created, err := interactor.GameRepository.Store(ctx, game)
 	if err != nil {
 		return 0, err, 500
 	}
-	err, code = interactor.GameRepository.AddToLib(id, libraryId)
+	game.Id = id
+	// A game the catalog already knows keeps its value, only its owners
+	// change it through UpdateGameValue
+	if created {
+		err = interactor.recordPrice(ctx, game, PriceSourceCatalog)
+		if err != nil {
+			return 0, err, 500
+		}
+	}
+	err, code = interactor.GameRepository.AddToLib(ctx, id, libraryId)
 	if err != nil {
 		return 0, err, code
 	}
+	err = interactor.unwish(ctx, user.Id, id)
+	if err != nil {
+		return 0, err, 500
+	}
+	interactor.recordEvent(ctx, EventGameAdded, library, id)
 
-	fmt.Println(fmt.Sprintf("User added game %s (id #%d) to library #%d",
-		game.Name, id, library.Id))
+	interactor.Loggr.Info("Game added", "gameId", id, "libraryId", library.Id)
 	return id, nil, 200
 }
 
-func (interactor *ProfileInteractor) PickGame(userId, libraryId, gameId int) (error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+func (interactor *ProfileInteractor) PickGame(ctx context.Context, userId, libraryId, gameId int) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "PickGame")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
 	if err != nil {
 		return err, code
 	}
-	library, err, code := interactor.LibraryRepository.FindById(libraryId)
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
 	if err != nil {
 		return err, code
 	}
-	_, err, code = interactor.GameRepository.FindById(gameId)
+	_, err, code = interactor.GameRepository.FindById(ctx, gameId)
 	if err != nil {
 		return err, code
 	}
-	if user.Id != library.User.Id {
-		message := "User #%d is not allowed to add games to library #%d of user #%d"
-		err := fmt.Errorf(message, user.Id, library.Id, library.User.Id)
-		return err, 403
+	err, code = checkLibraryOwner(user.Id, library, "add games to")
+	if err != nil {
+		return err, code
 	}
-	err, code = interactor.GameRepository.AddToLib(gameId, libraryId)
+	err, code = interactor.GameRepository.AddToLib(ctx, gameId, libraryId)
 	if err != nil {
 		return err, code
 	}
-	fmt.Println(fmt.Sprintf("User added game #%d to library #%d",
-		gameId, libraryId))
+	err = interactor.unwish(ctx, user.Id, gameId)
+	if err != nil {
+		return err, 500
+	}
+	interactor.recordEvent(ctx, EventGameAdded, library, gameId)
+	interactor.Loggr.Info("Game added", "gameId", gameId, "libraryId", libraryId)
 	return nil, 200
 }
 
-func (interactor *ProfileInteractor) RemoveGame(userId, libraryId, gameId int) (error, int) {
-	user, err, code := interactor.UserRepository.FindById(userId)
+func (interactor *ProfileInteractor) RemoveGame(ctx context.Context, userId, libraryId, gameId int) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RemoveGame")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
 	if err != nil {
-		// interactor.Logger.Log(err.Error())
 		return err, code
 	}
-	library, err, code := interactor.LibraryRepository.FindById(libraryId)
+	library, err, code := interactor.LibraryRepository.FindById(ctx, libraryId)
 	if err != nil {
-		// interactor.Logger.Log(err.Error())
 		return err, code
 	}
-	if user.Player.Id != library.User.Player.Id {
-		message := "User #%d is not allowed to remove games from library #%d of user #%d"
-		err := fmt.Errorf(message, user.Id, library.Id, library.User.Id)
-		// interactor.Logger.Log(err.Error())
-		return err, 403
+	err, code = checkLibraryOwner(user.Id, library, "remove games from")
+	if err != nil {
+		return err, code
 	}
-	game, err, code := interactor.GameRepository.FindById(gameId)
+	game, err, code := interactor.GameRepository.FindById(ctx, gameId)
 	if err != nil {
-		// interactor.Logger.Log(err.Error())
 		return err, code
 	}
 
-	err = interactor.GameRepository.RemoveFromLib(game, libraryId)
+	err = interactor.GameRepository.RemoveFromLib(ctx, game, libraryId)
+	if err != nil {
+		return err, 500
+	}
+	interactor.recordEvent(ctx, EventGameRemoved, library, game.Id)
+	detail := fmt.Sprintf("library #%d", library.Id)
+	err = interactor.audit(ctx, AuditGameRemoved, user.Id, "game", game.Id, detail)
 	if err != nil {
 		return err, 500
 	}
+	interactor.Loggr.Info("Game removed", "gameId", game.Id, "libraryId", library.Id)
 	return nil, 200
 }
 
-func (interactor *ProfileInteractor) FindLoginId(username, password string) (int, error, int) {
-	id, exist, err := interactor.UserRepository.FindLoginId(username, password)
+func (interactor *ProfileInteractor) FindLoginId(ctx context.Context, username, password string) (int, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "FindLoginId")
+	defer end()
+	id, exist, err := interactor.UserRepository.FindLoginId(ctx, username, password)
 	if err != nil {
 		return 0, err, 500
 	}
@@ -354,6 +465,6 @@ func (interactor *ProfileInteractor) FindLoginId(username, password string) (int
 		err := fmt.Errorf("Username/password incorrect")
 		return 0, err, 400
 	}
-	fmt.Printf("Found login id: #%d\n", id)
+	interactor.Loggr.Debug("Login id found", "loginUserId", id)
 	return id, nil, 200
 }
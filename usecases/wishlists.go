@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type WishlistRepository interface {
+	Store(ctx context.Context, entry WishlistEntry) error
+	Remove(ctx context.Context, userId, gameId int) error
+	FindByUser(ctx context.Context, userId int) ([]WishlistEntry, error)
+	Contains(ctx context.Context, userId, gameId int) (bool, error)
+	NotifyPriceDrop(ctx context.Context, gameId int, value float64, message string, createdAt time.Time) error
+	FindNotifications(ctx context.Context, userId int) ([]Notification, error)
+}
+
+type WishlistEntry struct {
+	UserId      int
+	GameId      int
+	TargetPrice *float64 //nil when the user has no price target
+	AddedAt     time.Time
+}
+
+type Notification struct {
+	Id        int
+	UserId    int
+	GameId    int
+	Message   string
+	Value     float64
+	CreatedAt time.Time
+}
+
+func (interactor *ProfileInteractor) AddToWishlist(ctx context.Context, userId, gameId int, targetPrice *float64) (WishlistEntry, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "AddToWishlist")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return WishlistEntry{}, err, code
+	}
+	_, err, code = interactor.GameRepository.FindById(ctx, gameId)
+	if err != nil {
+		return WishlistEntry{}, err, code
+	}
+	if targetPrice != nil && *targetPrice < 0 {
+		err := fmt.Errorf("Target price cannot be negative")
+		return WishlistEntry{}, err, 400
+	}
+	// Application rule: users only wish for games they do not own yet
+	owned, err := interactor.GameRepository.InUserLibraries(ctx, gameId, user.Id)
+	if err != nil {
+		return WishlistEntry{}, err, 500
+	}
+	if owned {
+		err := fmt.Errorf("User #%d already owns game #%d", user.Id, gameId)
+		return WishlistEntry{}, err, 400
+	}
+	existed, err := interactor.WishlistRepository.Contains(ctx, user.Id, gameId)
+	if err != nil {
+		return WishlistEntry{}, err, 500
+	}
+	if existed {
+		err := fmt.Errorf("Game already existed in wishlist")
+		return WishlistEntry{}, err, 400
+	}
+
+	entry := WishlistEntry{UserId: user.Id, GameId: gameId, TargetPrice: targetPrice,
+		AddedAt: time.Now().UTC()}
+	err = interactor.WishlistRepository.Store(ctx, entry)
+	if err != nil {
+		return WishlistEntry{}, err, 500
+	}
+	interactor.Loggr.Info("Game wished for", "gameId", gameId)
+	return entry, nil, 201
+}
+
+func (interactor *ProfileInteractor) ShowWishlist(ctx context.Context, userId int) ([]WishlistEntry, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowWishlist")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err, code
+	}
+	entries, err := interactor.WishlistRepository.FindByUser(ctx, user.Id)
+	if err != nil {
+		return nil, err, 500
+	}
+	return entries, nil, 200
+}
+
+func (interactor *ProfileInteractor) RemoveFromWishlist(ctx context.Context, userId, gameId int) (error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "RemoveFromWishlist")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return err, code
+	}
+	existed, err := interactor.WishlistRepository.Contains(ctx, user.Id, gameId)
+	if err != nil {
+		return err, 500
+	}
+	if !existed {
+		err := fmt.Errorf("Game #%d is not in the wishlist of user #%d", gameId, user.Id)
+		return err, 404
+	}
+	err = interactor.WishlistRepository.Remove(ctx, user.Id, gameId)
+	if err != nil {
+		return err, 500
+	}
+	return nil, 200
+}
+
+func (interactor *ProfileInteractor) ShowInbox(ctx context.Context, userId int) ([]Notification, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "ShowInbox")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err, code
+	}
+	notifications, err := interactor.WishlistRepository.FindNotifications(ctx, user.Id)
+	if err != nil {
+		return nil, err, 500
+	}
+	return notifications, nil, 200
+}
+
+// UpdateGameValue changes the value of a catalog game. When the value drops,
+// every user wishing for the game with a target above the new value is notified.
+func (interactor *ProfileInteractor) UpdateGameValue(ctx context.Context, userId, gameId int, value float64) (Game, error, int) {
+	ctx, end := interactor.Tracer.Start(ctx, "UpdateGameValue")
+	defer end()
+	user, err, code := interactor.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return Game{}, err, code
+	}
+	game, err, code := interactor.GameRepository.FindById(ctx, gameId)
+	if err != nil {
+		return Game{}, err, code
+	}
+	if value < 0 {
+		err := fmt.Errorf("Game value cannot be negative")
+		return Game{}, err, 400
+	}
+	// Application rule: only players owning a game can update its catalog value
+	owned, err := interactor.GameRepository.InUserLibraries(ctx, gameId, user.Id)
+	if err != nil {
+		return Game{}, err, 500
+	}
+	if !owned {
+		err := fmt.Errorf("User #%d does not own game #%d", user.Id, gameId)
+		return Game{}, err, 403
+	}
+
+	game, err = interactor.setGameValue(ctx, game, value, PriceSourceUser)
+	if err != nil {
+		return Game{}, err, 500
+	}
+	interactor.Loggr.Info("Game value set", "gameId", game.Id, "value", value)
+	return game, nil, 200
+}
+
+func (interactor *ProfileInteractor) notifyPriceDrop(ctx context.Context, game Game, oldValue float64) error {
+	if game.Value >= oldValue {
+		return nil
+	}
+	message := fmt.Sprintf("%s dropped from %.2f to %.2f", game.Name, oldValue, game.Value)
+	return interactor.WishlistRepository.NotifyPriceDrop(ctx, game.Id, game.Value, message,
+		time.Now().UTC())
+}
+
+// unwish removes a game the user now owns from their wishlist, if it was there.
+func (interactor *ProfileInteractor) unwish(ctx context.Context, userId, gameId int) error {
+	existed, err := interactor.WishlistRepository.Contains(ctx, userId, gameId)
+	if err != nil || !existed {
+		return err
+	}
+	return interactor.WishlistRepository.Remove(ctx, userId, gameId)
+}